@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"reflect"
+	"sync"
 )
 
 // Content-Type MIME of the most common data formats.
@@ -16,6 +17,10 @@ const (
 	MIMEMultipartPOSTForm = "multipart/form-data"               // form
 	MIMEPROTOBUF          = "application/x-protobuf"            // protobuf
 	MIMETOML              = "application/toml"                  // toml
+	MIMEMergePatchJSON    = "application/merge-patch+json"      // json merge patch
+	MIMEJSONPatch         = "application/json-patch+json"       // json patch
+	MIMEMultipartMixed    = "multipart/mixed"                   // multipart parts
+	MIMEMultipartRelated  = "multipart/related"                 // multipart parts
 
 	MIMEHTML  = "text/html"
 	MIMEPlain = "text/plain"
@@ -40,40 +45,106 @@ type URIBinder interface {
 // These implement the Binding interface and can be used to bind the data
 // present in the request to struct instances.
 var (
-	JSON          Binder    = jsonBinder{}
-	XML           Binder    = xmlBinding{}
-	YAML          Binder    = yamlBinding{}
-	Form          Binder    = formBinder{}
-	FormMultipart Binder    = formMultipartBinder{}
-	ProtoBuf      Binder    = protobufBinding{}
-	TOML          Binder    = tomlBinding{}
-	Query         Binder    = queryBinding{}
-	Header        Binder    = headerBinding{}
-	URI           URIBinder = uriBinding{}
+	JSON                Binder    = jsonBinder{}
+	LenientJSON         Binder    = jsonBinder{lenient: true} // coerces weakly typed JSON values
+	XML                 Binder    = xmlBinding{}
+	YAML                Binder    = yamlBinding{}
+	Form                Binder    = formBinder{}
+	FormMultipart       Binder    = formMultipartBinder{}
+	FormMultipartStream Binder    = formMultipartStreamBinder{}
+	MultipartMixed      Binder    = multipartPartsBinder{}
+	MultipartRelated    Binder    = multipartPartsBinder{}
+	ProtoBuf            Binder    = protobufBinding{}
+	TOML                Binder    = tomlBinding{}
+	MergePatch          Binder    = mergePatchBinding{}
+	JSONPatch           Binder    = jsonPatchBinding{}
+	Query               Binder    = queryBinding{}
+	Header              Binder    = headerBinding{}
+	URI                 URIBinder = uriBinding{}
 )
 
 var defaultBinder Binder = JSON
 
 var binders = map[string]Binder{
-	MIMEJSON:              JSON,          // json
-	MIMEYAML:              YAML,          // yaml
-	MIMEXML:               XML,           // xml
-	MIMEXML2:              XML,           // xml
-	MIMEMultipartPOSTForm: FormMultipart, // form
-	MIMEPOSTForm:          Form,          // form
-	MIMEPROTOBUF:          ProtoBuf,      // protobuf
-	MIMETOML:              TOML,          // toml
+	MIMEJSON:              JSON,             // json
+	MIMEYAML:              YAML,             // yaml
+	MIMEXML:               XML,              // xml
+	MIMEXML2:              XML,              // xml
+	MIMEMultipartPOSTForm: FormMultipart,    // form
+	MIMEPOSTForm:          Form,             // form
+	MIMEPROTOBUF:          ProtoBuf,         // protobuf
+	MIMETOML:              TOML,             // toml
+	MIMEMergePatchJSON:    MergePatch,       // json merge patch
+	MIMEJSONPatch:         JSONPatch,        // json patch
+	MIMEMultipartMixed:    MultipartMixed,   // multipart parts
+	MIMEMultipartRelated:  MultipartRelated, // multipart parts
+}
+
+// bindingBinder is implemented by the built-in binders which honour the
+// converters and options of a Binding, and report to the recorder of a
+// BindWithResult call.
+type bindingBinder interface {
+	withBinding(b *Binding, rec *recorder) Binder
+}
+
+// Binding binds request arguments with its own converters. The zero value is
+// ready to use, the package level Bind and binders share a default Binding.
+type Binding struct {
+	mu         sync.RWMutex
+	converters map[reflect.Type]ConverterFunc
+	strict     map[string]bool
+	duplicates DuplicatePolicy
+	codec      JSONCodec
+	limits     JSONLimits
+}
+
+// New returns a Binding which only knows the built-in converters.
+func New() *Binding {
+	return &Binding{}
+}
+
+var defaultBinding = New()
+
+// orDefault returns b, or the package level Binding when b is nil.
+func (b *Binding) orDefault() *Binding {
+	if b == nil {
+		return defaultBinding
+	}
+	return b
+}
+
+// use returns binder configured with the converters and options of b.
+func (b *Binding) use(binder Binder, rec *recorder) Binder {
+	if bb, ok := binder.(bindingBinder); ok {
+		return bb.withBinding(b, rec)
+	}
+	return binder
 }
 
 // Bind request arguments
 func Bind(req *http.Request, obj interface{}, params ...map[string][]string) error {
-	var binder = &binder{}
-	return binder.Bind(req, obj, params...)
+	return defaultBinding.Bind(req, obj, params...)
+}
+
+// Bind binds the request body, query, uri params and headers to obj.
+func (b *Binding) Bind(req *http.Request, obj interface{}, params ...map[string][]string) error {
+	return b.bind(req, obj, nil, params...)
 }
 
-type binder struct{}
+// BindWith binds the request to obj with binder, configured with the
+// converters and options of b.
+func (b *Binding) BindWith(req *http.Request, obj interface{}, binder Binder) error {
+	var rec *recorder
+	if b.hasStrict() {
+		rec = newRecorder()
+	}
+	if err := b.bindBody(req, obj, binder, rec); err != nil {
+		return err
+	}
+	return b.checkStrict(obj, rec)
+}
 
-func (binder *binder) Bind(req *http.Request, obj interface{}, params ...map[string][]string) (err error) {
+func (b *Binding) bind(req *http.Request, obj interface{}, rec *recorder, params ...map[string][]string) (err error) {
 
 	vPtr := reflect.ValueOf(obj)
 
@@ -81,22 +152,17 @@ func (binder *binder) Bind(req *http.Request, obj interface{}, params ...map[str
 		return ErrBindNonPointerValue
 	}
 
+	if rec == nil && b.hasStrict() {
+		rec = newRecorder()
+	}
+
 	// bind request body
 	// --------------------------------------------------------------------------
-	var contentType = filterFlags(req.Header.Get("Content-Type"))
-
-	if binder, exists := binders[contentType]; exists {
-		err = binder.Bind(req, obj)
-	} else {
-		if req.Method == http.MethodGet {
-			err = Form.Bind(req, obj)
-		} else if defaultBinder != nil {
-			err = defaultBinder.Bind(req, obj)
+	if binder := binderFor(req); binder != nil {
+		if err = b.bindBody(req, obj, binder, rec); err != nil {
+			return err
 		}
 	}
-	if err != nil {
-		return err
-	}
 
 	// bind request query, header and uri
 	// --------------------------------------------------------------------------
@@ -110,7 +176,7 @@ func (binder *binder) Bind(req *http.Request, obj interface{}, params ...map[str
 	}
 
 	if vPtr.Kind() != reflect.Struct {
-		return
+		return b.checkStrict(obj, rec)
 	}
 
 	var vType = vPtr.Type()
@@ -129,26 +195,26 @@ func (binder *binder) Bind(req *http.Request, obj interface{}, params ...map[str
 		}
 	}
 
-	if hasQueryField {
-		err = Query.Bind(req, obj)
+	if hasQueryField || b.isStrict("query") {
+		err = b.use(Query, rec).Bind(req, obj)
 		if err != nil {
 			return err
 		}
 	}
 
-	if hasURIField && len(params) > 0 {
-		err = URI.BindURI(params[0], obj)
+	if (hasURIField || b.isStrict("uri")) && len(params) > 0 {
+		err = uriBinding{b, rec}.BindURI(params[0], obj)
 		if err != nil {
 			return err
 		}
 	}
 
-	if hasHeaderField {
-		err = Header.Bind(req, obj)
+	if hasHeaderField || b.isStrict("header") {
+		err = b.use(Header, rec).Bind(req, obj)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return b.checkStrict(obj, rec)
 }
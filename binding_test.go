@@ -16,7 +16,7 @@ import (
 	"github.com/miclle/binding/testdata/protoexample"
 )
 
-var b = &binder{}
+var b = &Binding{}
 
 type FooStruct struct {
 	Foo string `json:"foo" yaml:"foo" form:"foo" xml:"foo" toml:"foo" query:"foo"`
@@ -343,7 +343,7 @@ func testQueryBindingBoolFail(t *testing.T, method, path, badPath, body, badBody
 	assert.Error(t, err)
 }
 
-func testBodyBinding(t *testing.T, b *binder, contentType, path, badPath, body, badBody string) {
+func testBodyBinding(t *testing.T, b *Binding, contentType, path, badPath, body, badBody string) {
 	obj := FooStruct{}
 	req := requestWithBody("POST", path, body)
 	req.Header.Set("Content-Type", contentType)
@@ -359,7 +359,7 @@ func testBodyBinding(t *testing.T, b *binder, contentType, path, badPath, body,
 	assert.Equal(t, "", obj.Foo)
 }
 
-func testBodyBindingFail(t *testing.T, b *binder, contentType, path, badPath, body, badBody string) {
+func testBodyBindingFail(t *testing.T, b *Binding, contentType, path, badPath, body, badBody string) {
 	obj := FooStruct{}
 	req := requestWithBody("POST", path, body)
 	req.Header.Set("Content-Type", contentType)
@@ -375,7 +375,7 @@ func testBodyBindingFail(t *testing.T, b *binder, contentType, path, badPath, bo
 	assert.Equal(t, "", obj.Foo)
 }
 
-func testBodyBindingStringMap(t *testing.T, b *binder, contentType, path, badPath, body, badBody string) {
+func testBodyBindingStringMap(t *testing.T, b *Binding, contentType, path, badPath, body, badBody string) {
 	obj := make(map[string]string)
 	req := requestWithBody("POST", path, body)
 	req.Header.Set("Content-Type", contentType)
@@ -406,7 +406,7 @@ func requestWithBody(method, path, body string) (req *http.Request) {
 	return
 }
 
-func testProtoBodyBinding(t *testing.T, b *binder, name, path, badPath, body, badBody string) {
+func testProtoBodyBinding(t *testing.T, b *Binding, name, path, badPath, body, badBody string) {
 	obj := protoexample.Test{}
 	req := requestWithBody("POST", path, body)
 	req.Header.Add("Content-Type", MIMEPROTOBUF)
@@ -427,7 +427,7 @@ func (h hook) Read([]byte) (int, error) {
 	return 0, errors.New("error")
 }
 
-func testProtoBodyBindingFail(t *testing.T, b *binder, name, path, badPath, body, badBody string) {
+func testProtoBodyBindingFail(t *testing.T, b *Binding, name, path, badPath, body, badBody string) {
 	obj := protoexample.Test{}
 	req := requestWithBody("POST", path, body)
 
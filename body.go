@@ -0,0 +1,196 @@
+package binding
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+var (
+	// ErrRawBodyType field with the body:"raw" tag is not a []byte or a string
+	ErrRawBodyType = errors.New(`body:"raw" field must be a []byte or a string`)
+
+	// ErrBodyPointer body binder can not decode the value at a JSON Pointer
+	ErrBodyPointer = errors.New("body binder does not support JSON Pointers")
+)
+
+// pointerBinder is implemented by the body binders which decode the value at
+// a JSON Pointer of the body, JSON, YAML and TOML.
+type pointerBinder interface {
+	bindAt(req *http.Request, pointer string, obj interface{}) error
+}
+
+// BindBodyAt decodes the value at the RFC 6901 JSON Pointer pointer of the
+// request body into obj, such as "/data/attributes" of a JSON:API document.
+// Nothing is set when the body has no such value.
+func BindBodyAt(req *http.Request, pointer string, obj interface{}) error {
+	return defaultBinding.BindBodyAt(req, pointer, obj)
+}
+
+// BindBodyAt decodes the value at the RFC 6901 JSON Pointer pointer of the
+// request body into obj, with the converters and options of b.
+func (b *Binding) BindBodyAt(req *http.Request, pointer string, obj interface{}) error {
+	if reflect.ValueOf(obj).Kind() != reflect.Ptr {
+		return ErrBindNonPointerValue
+	}
+	binder := binderFor(req)
+	if binder == nil {
+		return nil
+	}
+	var rec *recorder
+	if b.hasStrict() {
+		rec = newRecorder()
+	}
+	if err := b.bindTarget(req, binder, bodyField{value: reflect.ValueOf(obj), pointer: pointer}, rec); err != nil {
+		return err
+	}
+	return b.checkStrict(obj, rec)
+}
+
+// binderFor returns the body binder for the Content-Type of req, nil when
+// there is none.
+func binderFor(req *http.Request) Binder {
+	if binder, exists := binders[filterFlags(req.Header.Get("Content-Type"))]; exists {
+		return binder
+	}
+	if req.Method == http.MethodGet {
+		return Form
+	}
+	return defaultBinder
+}
+
+// bindBody binds the request body to obj with binder.
+//
+// Fields with an empty body tag, body:"", receive the decoded body in place of
+// obj, which leaves the rest of obj to query, header and uri values, and
+// fields with a JSON Pointer, body:"/data/attributes", the value at the
+// pointer.
+//
+// Fields tagged body:"raw", []byte or json.RawMessage ones, and string ones
+// with body:"raw,string", receive the exact bytes the binder decoded, and the
+// body of req can be read again afterwards.
+func (b *Binding) bindBody(req *http.Request, obj interface{}, binder Binder, rec *recorder) error {
+	fields := bodyFields(reflect.ValueOf(obj), "")
+	targets, raws := fields.targets, fields.raws
+	if len(targets) == 0 {
+		targets = []bodyField{{value: reflect.ValueOf(obj)}}
+	}
+	if len(targets) == 1 && len(raws) == 0 || req.Body == nil || req.Body == http.NoBody {
+		for _, target := range targets {
+			if err := b.bindTarget(req, binder, target, rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		if err := b.bindTarget(req, binder, target, rec); err != nil {
+			return err
+		}
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+
+	for _, raw := range raws {
+		switch {
+		case raw.value.Kind() == reflect.String:
+			raw.value.SetString(string(data))
+		case raw.value.Kind() == reflect.Slice && raw.value.Type().Elem().Kind() == reflect.Uint8:
+			raw.value.SetBytes(data)
+		default:
+			return ErrRawBodyType
+		}
+		rec.set(raw.path, "body", "raw", false)
+	}
+	return nil
+}
+
+// bindTarget binds the body, or its value at the pointer of target, to target.
+func (b *Binding) bindTarget(req *http.Request, binder Binder, target bodyField, rec *recorder) (err error) {
+	binder = b.use(binder, rec.at(target.path))
+	if target.pointer == "" {
+		err = binder.Bind(req, target.value.Interface())
+	} else if pb, ok := binder.(pointerBinder); ok {
+		err = pb.bindAt(req, target.pointer, target.value.Interface())
+	} else {
+		return ErrBodyPointer
+	}
+
+	var bodyErr *BodyError
+	if errors.As(err, &bodyErr) && target.path != "" {
+		if bodyErr.Field == "" || bodyErr.Field[0] == '[' {
+			bodyErr.Field = target.path + bodyErr.Field
+		} else {
+			bodyErr.Field = target.path + "." + bodyErr.Field
+		}
+	}
+	return err
+}
+
+// bodyField is a field with the body tag, its path and the JSON Pointer of
+// the value it takes.
+type bodyField struct {
+	value   reflect.Value
+	path    string
+	pointer string
+}
+
+// bodyFieldSet holds the body fields of a struct: targets, pointers to the
+// fields the body decodes into, and raws, the fields receiving the raw body.
+type bodyFieldSet struct {
+	targets []bodyField
+	raws    []bodyField
+}
+
+// bodyFields returns the fields of value, and of its embedded structs, with
+// the body tag. Nil pointers to the whole body are allocated.
+func bodyFields(value reflect.Value, path string) (fields bodyFieldSet) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous {
+			embedded := bodyFields(value.Field(i), path)
+			fields.targets = append(fields.targets, embedded.targets...)
+			fields.raws = append(fields.raws, embedded.raws...)
+			continue
+		}
+		tag, ok := sf.Tag.Lookup("body")
+		if !ok || sf.PkgPath != "" {
+			continue
+		}
+		name, _ := head(tag, ",")
+		field := bodyField{value.Field(i), joinFieldPath(path, sf.Name), name}
+		switch name {
+		case "raw":
+			fields.raws = append(fields.raws, field)
+		default:
+			// pointer binders allocate pointers once they find a value
+			for field.pointer == "" && field.value.Kind() == reflect.Ptr {
+				if field.value.IsNil() {
+					field.value.Set(reflect.New(field.value.Type().Elem()))
+				}
+				field.value = field.value.Elem()
+			}
+			field.value = field.value.Addr()
+			fields.targets = append(fields.targets, field)
+		}
+	}
+	return
+}
@@ -0,0 +1,376 @@
+package binding
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BodyError locates an error decoding a JSON, YAML or TOML body.
+type BodyError struct {
+	// Format is the body format: "json", "yaml" or "toml".
+	Format string
+
+	// Offset is the byte offset of the failing value in the body.
+	Offset int64
+
+	// Line and Column locate the failing value, counting from 1. Column is 0
+	// when the decoder reports the line only.
+	Line   int
+	Column int
+
+	// Path is the JSON path of the failing value, such as "$.items[3].price",
+	// empty when the decoder does not report it.
+	Path string
+
+	// Field is the path of the Go field the value targets, such as
+	// "Items[3].Price".
+	Field string
+
+	Err error
+}
+
+func (e *BodyError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Format)
+	b.WriteString(": line ")
+	b.WriteString(strconv.Itoa(e.Line))
+	if e.Column > 0 {
+		b.WriteString(", column ")
+		b.WriteString(strconv.Itoa(e.Column))
+	}
+	if e.Path != "" {
+		b.WriteString(", ")
+		b.WriteString(e.Path)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *BodyError) Unwrap() error {
+	return e.Err
+}
+
+// pathElem is an element of the path of a value in a document, an object
+// key, or an array index when index is not negative.
+type pathElem struct {
+	key   string
+	index int
+}
+
+func (p pathElem) token() string {
+	if p.index >= 0 {
+		return strconv.Itoa(p.index)
+	}
+	return p.key
+}
+
+// jsonPointerOf returns path as a JSON Pointer, such as "/items/3/price".
+func jsonPointerOf(path []pathElem) string {
+	var b strings.Builder
+	for _, p := range path {
+		b.WriteString("/" + escapeJSONPointer(p.token()))
+	}
+	return b.String()
+}
+
+// formatJSONPath returns path as a JSON path, such as "$.items[3].price".
+func formatJSONPath(path []pathElem) string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, p := range path {
+		switch {
+		case p.index >= 0:
+			b.WriteString("[" + strconv.Itoa(p.index) + "]")
+		case isJSONPathName(p.key):
+			b.WriteString("." + p.key)
+		default:
+			b.WriteString("['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(p.key) + "']")
+		}
+	}
+	return b.String()
+}
+
+func isJSONPathName(key string) bool {
+	for i, r := range key {
+		if r != '_' && !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || i > 0 && '0' <= r && r <= '9') {
+			return false
+		}
+	}
+	return key != ""
+}
+
+// fieldPath returns the path of the Go field of t which the value at path of
+// a document of format sets, as far as t has fields for it.
+func (f *bodyFormat) fieldPath(t reflect.Type, path []pathElem) string {
+	var field string
+	for _, p := range path {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch {
+		case t.Kind() == reflect.Struct && p.index < 0 && !f.leaf(t):
+			sf, ok := f.field(t, p.key)
+			if !ok {
+				return field
+			}
+			field, t = joinFieldPath(field, sf.Name), sf.Type
+		case t.Kind() == reflect.Map:
+			field, t = field+"["+p.token()+"]", t.Elem()
+		case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && p.index >= 0:
+			field, t = field+"["+p.token()+"]", t.Elem()
+		default:
+			return field
+		}
+	}
+	return field
+}
+
+// lineColumn returns the line and column of offset in data, counting from 1.
+func lineColumn(data []byte, offset int64) (line, column int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	return bytes.Count(before, []byte("\n")) + 1, int(offset) - bytes.LastIndexByte(before, '\n')
+}
+
+// lineOffset returns the offset of line and column in data, counting from 1.
+func lineOffset(data []byte, line, column int) int64 {
+	var offset int
+	for ; line > 1; line-- {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return int64(len(data))
+		}
+		offset += i + 1
+	}
+	if column > 1 {
+		offset += column - 1
+	}
+	if offset > len(data) {
+		offset = len(data)
+	}
+	return int64(offset)
+}
+
+// jsonToken is a token of a JSON document.
+type jsonToken struct {
+	stdjson.Token
+
+	// Key is set for object keys, End for closing delimiters.
+	Key, End bool
+
+	// Depth is the number of containers around the token.
+	Depth int
+
+	// Start is the offset of the token.
+	Start int64
+
+	path func() []pathElem
+}
+
+// Path returns the path of the value of the token: the value a key names,
+// the container a closing delimiter ends.
+func (t jsonToken) Path() []pathElem {
+	return t.path()
+}
+
+// walkJSON calls fn with every token of the JSON document data, in document
+// order, until fn returns false. It returns the syntax error of data.
+func walkJSON(data []byte, fn func(tok jsonToken) bool) error {
+	type frame struct {
+		pathElem
+		object, key bool // key is set when an object expects a key
+	}
+	var stack []frame
+	path := func() []pathElem {
+		elems := make([]pathElem, len(stack))
+		for i, f := range stack {
+			elems[i] = f.pathElem
+		}
+		return elems
+	}
+
+	decoder := stdjson.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for start < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[start]) >= 0 {
+			start++
+		}
+		tok := jsonToken{Token: token, Depth: len(stack), Start: start, path: path}
+
+		if delim, ok := token.(stdjson.Delim); ok && (delim == '}' || delim == ']') {
+			stack = stack[:len(stack)-1]
+			tok.End, tok.Depth = true, len(stack)
+			if !fn(tok) {
+				return nil
+			}
+			if n := len(stack); n > 0 && stack[n-1].object {
+				stack[n-1].key = true
+			}
+			continue
+		}
+
+		if n := len(stack); n > 0 {
+			top := &stack[n-1]
+			if top.object && top.key {
+				top.pathElem, top.key = pathElem{key: token.(string), index: -1}, false
+				tok.Key = true
+				if !fn(tok) {
+					return nil
+				}
+				continue
+			}
+			if !top.object {
+				top.index++
+			}
+		}
+		if !fn(tok) {
+			return nil
+		}
+
+		switch token {
+		case stdjson.Delim('{'):
+			stack = append(stack, frame{pathElem: pathElem{index: -1}, object: true, key: true})
+		case stdjson.Delim('['):
+			stack = append(stack, frame{pathElem: pathElem{index: -1}})
+		default:
+			if n := len(stack); n > 0 && stack[n-1].object {
+				stack[n-1].key = true
+			}
+		}
+	}
+}
+
+// scanJSON calls fn with the path and the offset of every value of the JSON
+// document data, in document order, until fn returns false. It returns the
+// syntax error of data.
+func scanJSON(data []byte, fn func(path []pathElem, start int64) bool) error {
+	return walkJSON(data, func(tok jsonToken) bool {
+		return tok.Key || tok.End || fn(tok.Path(), tok.Start)
+	})
+}
+
+// jsonPathAt returns the path of the innermost value of data which starts
+// before offset.
+func jsonPathAt(data []byte, offset int64) []pathElem {
+	var path []pathElem
+	scanJSON(data, func(p []pathElem, start int64) bool {
+		if start >= offset {
+			return false
+		}
+		path = p
+		return true
+	})
+	return path
+}
+
+// jsonValueAt returns the path and offset of the value of data at the
+// reference tokens of a JSON Pointer.
+func jsonValueAt(data []byte, tokens []string) (path []pathElem, offset int64, ok bool) {
+	scanJSON(data, func(p []pathElem, start int64) bool {
+		if len(p) != len(tokens) {
+			return true
+		}
+		for i, elem := range p {
+			if elem.token() != tokens[i] {
+				return true
+			}
+		}
+		path, offset, ok = p, start, true
+		return false
+	})
+	return
+}
+
+// jsonError locates err, an error decoding data into obj, in the body. data
+// is the body, or its value at pointer, possibly re-encoded. Errors which
+// can not be located are returned as is.
+func jsonError(err error, body []byte, pointer string, data []byte, obj interface{}) error {
+	prefix, _ := parseJSONPointer(pointer)
+	t := reflect.TypeOf(obj)
+	if t == nil || len(body) == 0 {
+		return err
+	}
+
+	var last []pathElem
+	var syntax *stdjson.SyntaxError
+	if errors.As(scanJSON(body, func(p []pathElem, _ int64) bool {
+		last = p
+		return true
+	}), &syntax) {
+		return newJSONBodyError(err, body, syntax.Offset, last, prefix, t)
+	}
+
+	var tokens []string
+	var conv *ConversionError
+	if errors.As(err, &conv) && strings.HasPrefix(conv.Key, "/") {
+		tokens, _ = parseJSONPointer(conv.Key)
+	} else {
+		offset, ok := jsonErrorOffset(data, t)
+		if !ok {
+			return err
+		}
+		tokens = append([]string(nil), prefix...)
+		for _, p := range jsonPathAt(data, offset) {
+			tokens = append(tokens, p.token())
+		}
+	}
+	path, offset, ok := jsonValueAt(body, tokens)
+	if !ok {
+		return err
+	}
+	return newJSONBodyError(err, body, offset, path, prefix, t)
+}
+
+func newJSONBodyError(err error, body []byte, offset int64, path []pathElem, prefix []string, t reflect.Type) *BodyError {
+	e := &BodyError{Format: "json", Offset: offset, Path: formatJSONPath(path), Err: err}
+	e.Line, e.Column = lineColumn(body, offset)
+	if t == nil || len(path) < len(prefix) {
+		return e
+	}
+	for i, token := range prefix {
+		if path[i].token() != token {
+			return e
+		}
+	}
+	e.Field = jsonFormat.fieldPath(t, path[len(prefix):])
+	return e
+}
+
+// jsonErrorOffset decodes data into a new value of t, a pointer type, with
+// encoding/json, which reports the offsets of the values failing to decode.
+func jsonErrorOffset(data []byte, t reflect.Type) (int64, bool) {
+	if t.Kind() != reflect.Ptr {
+		return 0, false
+	}
+	decoder := stdjson.NewDecoder(bytes.NewReader(data))
+	if EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+	err := decoder.Decode(reflect.New(t.Elem()).Interface())
+
+	var syntax *stdjson.SyntaxError
+	var typeErr *stdjson.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntax):
+		return syntax.Offset, true
+	case errors.As(err, &typeErr):
+		return typeErr.Offset, true
+	}
+	return 0, false
+}
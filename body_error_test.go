@@ -0,0 +1,88 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type errorOrder struct {
+	Items []struct {
+		Price float64 `json:"price" yaml:"price" toml:"price"`
+	} `json:"items" yaml:"items" toml:"items"`
+}
+
+func bindBodyError(t *testing.T, contentType, body string, obj interface{}) *BodyError {
+	req := requestWithBody("POST", "/", body)
+	req.Header.Set("Content-Type", contentType)
+	err := Bind(req, obj)
+	var bodyErr *BodyError
+	if assert.ErrorAs(t, err, &bodyErr) {
+		return bodyErr
+	}
+	return &BodyError{}
+}
+
+func TestJSONBodyError(t *testing.T) {
+	body := "{\n  \"items\": [\n    {\"price\": 1},\n    {\"price\": \"free\"}\n  ]\n}"
+	for _, codec := range []JSONCodec{JSONIter, StdJSON} {
+		SetJSONCodec(codec)
+		var order errorOrder
+		err := bindBodyError(t, MIMEJSON, body, &order)
+		assert.Equal(t, "json", err.Format)
+		assert.Equal(t, int64(47), err.Offset)
+		assert.Equal(t, 4, err.Line)
+		assert.Equal(t, 15, err.Column)
+		assert.Equal(t, "$.items[1].price", err.Path)
+		assert.Equal(t, "Items[1].Price", err.Field)
+	}
+	SetJSONCodec(nil)
+
+	var order errorOrder
+	err := bindBodyError(t, MIMEJSON, "{\n  \"items\": [\n    {\"price\": 1,}\n  ]\n}", &order)
+	assert.Equal(t, 3, err.Line)
+	assert.Equal(t, 17, err.Column)
+	assert.Equal(t, "$.items[0].price", err.Path)
+
+	var s struct {
+		Payload errorOrder `body:"/data"`
+	}
+	err = bindBodyError(t, MIMEJSON, `{"data": {"items": [{"price": true}]}}`, &s)
+	assert.Equal(t, "$.data.items[0].price", err.Path)
+	assert.Equal(t, "Payload.Items[0].Price", err.Field)
+	assert.Equal(t, 1, err.Line)
+	assert.Equal(t, 31, err.Column)
+
+	var lenient struct {
+		Page int `json:"page,lenient"`
+	}
+	err = bindBodyError(t, MIMEJSON, `{"page": "two"}`, &lenient)
+	assert.Equal(t, "$.page", err.Path)
+	assert.Equal(t, "Page", err.Field)
+	assert.Equal(t, 10, err.Column)
+}
+
+func TestYAMLBodyError(t *testing.T) {
+	var order errorOrder
+	err := bindBodyError(t, MIMEYAML, "items:\n  - price: 1\n  - price: free\n", &order)
+	assert.Equal(t, "yaml", err.Format)
+	assert.Equal(t, 3, err.Line)
+	assert.Equal(t, 12, err.Column)
+	assert.Equal(t, int64(31), err.Offset)
+	assert.Equal(t, "$.items[1].price", err.Path)
+	assert.Equal(t, "Items[1].Price", err.Field)
+
+	err = bindBodyError(t, MIMEYAML, "items:\n  - price: 1\n  - price: a: b\n", &order)
+	assert.Equal(t, 3, err.Line)
+	assert.Equal(t, 0, err.Column)
+	assert.Equal(t, int64(20), err.Offset)
+}
+
+func TestTOMLBodyError(t *testing.T) {
+	var order errorOrder
+	err := bindBodyError(t, MIMETOML, "[[items]]\nprice = 1.0\n[[items]]\nprice = = 2\n", &order)
+	assert.Equal(t, "toml", err.Format)
+	assert.Equal(t, 4, err.Line)
+	assert.Equal(t, 9, err.Column)
+	assert.Equal(t, int64(40), err.Offset)
+}
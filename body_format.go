@@ -0,0 +1,102 @@
+package binding
+
+import (
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// bodyFormat describes how a body format matches the keys of an object to
+// the fields of a struct.
+type bodyFormat struct {
+	name   string // source name, also the struct tag
+	fold   bool   // keys match field names case-insensitively
+	lower  bool   // the default name is the lowercased field name
+	inline bool   // embedded structs are promoted only with the inline option
+	leaf   func(t reflect.Type) bool
+}
+
+var (
+	jsonFormat = &bodyFormat{name: "json", fold: true, leaf: isJSONLeaf}
+	yamlFormat = &bodyFormat{name: "yaml", lower: true, inline: true, leaf: isYAMLLeaf}
+	tomlFormat = &bodyFormat{name: "toml", fold: true, leaf: isTOMLLeaf}
+)
+
+var yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+
+// isYAMLLeaf reports whether values of t decode themselves from YAML.
+func isYAMLLeaf(t reflect.Type) bool {
+	return t == timeType ||
+		reflect.PtrTo(t).Implements(yamlUnmarshalerType) ||
+		reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// isTOMLLeaf reports whether values of t decode themselves from TOML.
+func isTOMLLeaf(t reflect.Type) bool {
+	return t == timeType || reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// field finds the field of struct type t which decodes the object key, exact
+// names first, then case-insensitive ones for formats which fold.
+func (f *bodyFormat) field(t reflect.Type, key string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	var folded bool
+	for _, sf := range f.fields(t) {
+		name := f.fieldName(sf)
+		if name == key {
+			return sf, true
+		}
+		if f.fold && !folded && strings.EqualFold(name, key) {
+			fold, folded = sf, true
+		}
+	}
+	return fold, folded
+}
+
+// fields returns the exported fields of t, with the fields of embedded
+// structs promoted. The Index of a promoted field is relative to t.
+func (f *bodyFormat) fields(t reflect.Type) []reflect.StructField {
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(f.name)
+		if tag == "-" {
+			continue
+		}
+		name, opts := head(tag, ",")
+		promote := sf.Anonymous && name == ""
+		if f.inline {
+			promote = strings.Contains(","+opts+",", ",inline,")
+		}
+		if promote {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for _, promoted := range f.fields(ft) {
+					promoted.Index = append([]int{i}, promoted.Index...)
+					fields = append(fields, promoted)
+				}
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		fields = append(fields, sf)
+	}
+	return fields
+}
+
+// fieldName returns the key of sf in the format.
+func (f *bodyFormat) fieldName(sf reflect.StructField) string {
+	if name, _ := head(sf.Tag.Get(f.name), ","); name != "" {
+		return name
+	}
+	if f.lower {
+		return strings.ToLower(sf.Name)
+	}
+	return sf.Name
+}
@@ -0,0 +1,164 @@
+package binding
+
+import (
+	stdjson "encoding/json"
+	"io"
+	"testing"
+
+	"github.com/miclle/binding/testdata/protoexample"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBindingRawBody(t *testing.T) {
+	var s struct {
+		Name    string             `json:"name" form:"name"`
+		Raw     []byte             `json:"-" body:"raw"`
+		Text    string             `json:"-" body:"raw,string"`
+		Message stdjson.RawMessage `json:"-" body:"raw"`
+	}
+
+	body := `{"name": "raw"}`
+	req := requestWithBody("POST", "/", body)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.NoError(t, Bind(req, &s))
+	assert.Equal(t, "raw", s.Name)
+	assert.Equal(t, []byte(body), s.Raw)
+	assert.Equal(t, body, s.Text)
+	assert.Equal(t, stdjson.RawMessage(body), s.Message)
+
+	data, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(data))
+
+	body = "name=form"
+	req = requestWithBody("POST", "/", body)
+	req.Header.Set("Content-Type", MIMEPOSTForm)
+	assert.NoError(t, New().BindWith(req, &s, Form))
+	assert.Equal(t, "form", s.Name)
+	assert.Equal(t, body, s.Text)
+
+	var invalid struct {
+		Raw int `body:"raw"`
+	}
+	req = requestWithBody("POST", "/", "{}")
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.ErrorIs(t, Bind(req, &invalid), ErrRawBodyType)
+}
+
+func TestBindingRawBodyProtoBuf(t *testing.T) {
+	label := "yes"
+	data, err := proto.Marshal(&protoexample.Test{Label: &label})
+	assert.NoError(t, err)
+
+	s := struct {
+		*protoexample.Test
+		Raw []byte `body:"raw"`
+	}{Test: &protoexample.Test{}}
+	req := requestWithBody("POST", "/", string(data))
+	req.Header.Set("Content-Type", MIMEPROTOBUF)
+	assert.NoError(t, Bind(req, &s))
+	assert.Equal(t, "yes", s.GetLabel())
+	assert.Equal(t, data, s.Raw)
+}
+
+func TestBindingBodyField(t *testing.T) {
+	type createUser struct {
+		Name  string `json:"name" form:"name"`
+		Email string `json:"email" form:"email"`
+	}
+	var s struct {
+		Page    int        `query:"page"`
+		Token   string     `header:"X-Token"`
+		Name    string     `query:"name"`
+		Payload createUser `body:""`
+		Raw     string     `body:"raw,string"`
+	}
+
+	body := `{"name": "body", "email": "body@example.com"}`
+	req := requestWithBody("POST", "/?page=2&name=query", body)
+	req.Header.Set("Content-Type", MIMEJSON)
+	req.Header.Set("X-Token", "secret")
+	result, err := BindWithResult(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.Page)
+	assert.Equal(t, "secret", s.Token)
+	assert.Equal(t, "query", s.Name)
+	assert.Equal(t, createUser{"body", "body@example.com"}, s.Payload)
+	assert.Equal(t, body, s.Raw)
+	assert.Equal(t, FieldSource{Source: "json", Key: "/name"}, result.Fields["Payload.Name"])
+	assert.Equal(t, FieldSource{Source: "query", Key: "name"}, result.Fields["Name"])
+
+	var p struct {
+		Payload *createUser `body:""`
+	}
+	req = requestWithBody("POST", "/?name=query", "name=form")
+	req.Header.Set("Content-Type", MIMEPOSTForm)
+	assert.NoError(t, Bind(req, &p))
+	assert.Equal(t, &createUser{Name: "form"}, p.Payload)
+}
+
+type envelopeAttributes struct {
+	Name string   `json:"name" yaml:"name" toml:"name"`
+	Tags []string `json:"tags" yaml:"tags" toml:"tags"`
+}
+
+func TestBindingBodyPointer(t *testing.T) {
+	var s struct {
+		ID         string              `query:"id"`
+		Type       string              `body:"/data/type"`
+		Attributes envelopeAttributes  `body:"/data/attributes"`
+		Tag        string              `body:"/data/attributes/tags/1"`
+		Missing    *envelopeAttributes `body:"/meta"`
+	}
+
+	for contentType, body := range map[string]string{
+		MIMEJSON: `{"data": {"type": "users", "id": "1", "attributes": {"name": "envelope", "tags": ["a", "b"]}, "links": [{}]}}`,
+		MIMEYAML: "data:\n  type: users\n  attributes:\n    name: envelope\n    tags: [a, b]\n",
+		MIMETOML: "[data]\ntype = \"users\"\n[data.attributes]\nname = \"envelope\"\ntags = [\"a\", \"b\"]\n",
+	} {
+		s.Missing = nil
+		req := requestWithBody("POST", "/?id=1", body)
+		req.Header.Set("Content-Type", contentType)
+		result, err := BindWithResult(req, &s)
+		assert.NoError(t, err, contentType)
+		assert.Equal(t, "1", s.ID)
+		assert.Equal(t, "users", s.Type, contentType)
+		assert.Equal(t, envelopeAttributes{"envelope", []string{"a", "b"}}, s.Attributes, contentType)
+		assert.Equal(t, "b", s.Tag, contentType)
+		assert.Nil(t, s.Missing, contentType)
+		assert.Equal(t, "/data/attributes/name", result.Fields["Attributes.Name"].Key, contentType)
+	}
+
+	var invalid struct {
+		Name string `body:"data"`
+	}
+	req := requestWithBody("POST", "/", `{"data": "x"}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.ErrorIs(t, Bind(req, &invalid), ErrInvalidJSONPointer)
+
+	var xml struct {
+		Name string `body:"/data"`
+	}
+	req = requestWithBody("POST", "/", `<data>x</data>`)
+	req.Header.Set("Content-Type", MIMEXML)
+	assert.ErrorIs(t, Bind(req, &xml), ErrBodyPointer)
+}
+
+func TestBindBodyAt(t *testing.T) {
+	var attributes envelopeAttributes
+	req := requestWithBody("POST", "/", `{"data": [{"attributes": {"name": "first"}}, {"attributes": {"name": "second"}}]}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.NoError(t, BindBodyAt(req, "/data/1/attributes", &attributes))
+	assert.Equal(t, "second", attributes.Name)
+
+	var name string
+	req = requestWithBody("POST", "/", `{"a/b": {"c~d": "escaped"}}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.NoError(t, BindBodyAt(req, "/a~1b/c~0d", &name))
+	assert.Equal(t, "escaped", name)
+
+	req = requestWithBody("POST", "/", `{"data": {"attributes": `)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.Error(t, BindBodyAt(req, "/data/attributes", &attributes))
+}
@@ -0,0 +1,212 @@
+package binding
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bodyTime is a time of a body parsed by the time tags of its field, and
+// the path of its value in the body.
+type bodyTime struct {
+	path []pathElem
+	time time.Time
+}
+
+var timeTagTypes sync.Map // reflect.Type → bool
+
+// hasTimeTags reports whether a value of t has time fields with the
+// time_format, time_utc or time_location tag.
+func hasTimeTags(t reflect.Type) bool {
+	if has, ok := timeTagTypes.Load(t); ok {
+		return has.(bool)
+	}
+	has := hasTimeTagsOf(t, make(map[reflect.Type]bool))
+	timeTagTypes.Store(t, has)
+	return has
+}
+
+func hasTimeTagsOf(t reflect.Type, seen map[reflect.Type]bool) bool {
+	t = timeElem(t)
+	if t.Kind() != reflect.Struct || t == timeType || seen[t] {
+		return false
+	}
+	seen[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if isTimeTagged(sf) && timeElem(sf.Type) == timeType || hasTimeTagsOf(sf.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeElem returns t without its pointers, slices, arrays and maps.
+func timeElem(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	return t
+}
+
+func isTimeTagged(sf reflect.StructField) bool {
+	return sf.Tag.Get("time_format") != "" || sf.Tag.Get("time_utc") != "" || sf.Tag.Get("time_location") != ""
+}
+
+// parseBodyTime parses v, a value of a decoded body, by the time tags of sf
+// like setTimeField parses form values. Times the format decoded already are
+// formatted by the tags first, so time_location applies to them too.
+func parseBodyTime(v interface{}, sf reflect.StructField) (time.Time, error) {
+	var val string
+	switch v := v.(type) {
+	case string:
+		val = v
+	case time.Time:
+		switch timeFormat := strings.ToLower(sf.Tag.Get("time_format")); timeFormat {
+		case "unix":
+			val = strconv.FormatInt(v.Unix(), 10)
+		case "unixnano":
+			val = strconv.FormatInt(v.UnixNano(), 10)
+		case "":
+			val = v.Format(time.RFC3339Nano)
+		default:
+			val = v.Format(sf.Tag.Get("time_format"))
+		}
+	case fmt.Stringer:
+		val = v.String()
+	default:
+		val = fmt.Sprint(v)
+	}
+
+	var t time.Time
+	err := setTimeField(val, sf, reflect.ValueOf(&t).Elem())
+	return t, err
+}
+
+// parseDocTimes replaces the values of doc, a body of format decoded into
+// maps and slices, which set time fields of t with time tags by the times
+// they parse to. The times are appended to times, as decoding keeps their
+// instants but not always their locations. sf is the time tagged field doc
+// sets, nil for others.
+func parseDocTimes(format *bodyFormat, doc interface{}, t reflect.Type, sf *reflect.StructField, path []pathElem, pointer string, times *[]bodyTime) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		if sf == nil || doc == nil {
+			return doc, nil
+		}
+		tm, err := parseBodyTime(doc, *sf)
+		if err != nil {
+			return nil, conversionError(pointer, []string{fmt.Sprint(doc)}, t, err)
+		}
+		*times = append(*times, bodyTime{path, tm})
+		return tm, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := doc.(map[string]interface{})
+		if !ok || format.leaf(t) {
+			return doc, nil
+		}
+		for key, v := range object {
+			field, ok := format.field(t, key)
+			if !ok {
+				continue
+			}
+			var tagged *reflect.StructField
+			if isTimeTagged(field) {
+				tagged = &field
+			}
+			elem := pathElem{key: key, index: -1}
+			v, err := parseDocTimes(format, v, field.Type, tagged, append(path[:len(path):len(path)], elem), pointer+"/"+escapeJSONPointer(key), times)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = v
+		}
+	case reflect.Map:
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return doc, nil
+		}
+		for key, v := range object {
+			elem := pathElem{key: key, index: -1}
+			v, err := parseDocTimes(format, v, t.Elem(), sf, append(path[:len(path):len(path)], elem), pointer+"/"+escapeJSONPointer(key), times)
+			if err != nil {
+				return nil, err
+			}
+			object[key] = v
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := doc.([]interface{})
+		if !ok {
+			return doc, nil
+		}
+		for i, v := range array {
+			elem := pathElem{index: i}
+			v, err := parseDocTimes(format, v, t.Elem(), sf, append(path[:len(path):len(path)], elem), pointer+"/"+strconv.Itoa(i), times)
+			if err != nil {
+				return nil, err
+			}
+			array[i] = v
+		}
+	}
+	return doc, nil
+}
+
+// setBodyTimes sets times into obj, which a body of format decoded into.
+func setBodyTimes(format *bodyFormat, obj interface{}, times []bodyTime) {
+	for _, bt := range times {
+		setTimeAt(format, reflect.ValueOf(obj), bt.path, bt.time)
+	}
+}
+
+func setTimeAt(format *bodyFormat, value reflect.Value, path []pathElem, t time.Time) {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if len(path) == 0 {
+		if value.Type() == timeType && value.CanSet() {
+			value.Set(reflect.ValueOf(t))
+		}
+		return
+	}
+
+	p := path[0]
+	switch value.Kind() {
+	case reflect.Struct:
+		sf, ok := format.field(value.Type(), p.key)
+		if !ok {
+			return
+		}
+		if value, ok = fieldByIndex(value, sf.Index); ok {
+			setTimeAt(format, value, path[1:], t)
+		}
+	case reflect.Slice, reflect.Array:
+		if p.index >= 0 && p.index < value.Len() {
+			setTimeAt(format, value.Index(p.index), path[1:], t)
+		}
+	case reflect.Map:
+		key := reflect.ValueOf(p.key)
+		if !key.Type().ConvertibleTo(value.Type().Key()) {
+			return
+		}
+		key = key.Convert(value.Type().Key())
+		elem := value.MapIndex(key)
+		if !elem.IsValid() {
+			return
+		}
+		copied := reflect.New(elem.Type()).Elem()
+		copied.Set(elem)
+		setTimeAt(format, copied, path[1:], t)
+		value.SetMapIndex(key, copied)
+	}
+}
@@ -0,0 +1,75 @@
+package binding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type timedEvent struct {
+	Day     time.Time            `json:"day" yaml:"day" toml:"day" form:"day" time_format:"2006-01-02" time_location:"Asia/Shanghai"`
+	Unix    time.Time            `json:"unix" yaml:"unix" toml:"unix" form:"unix" time_format:"unix"`
+	Nano    *time.Time           `json:"nano" yaml:"nano" toml:"nano" form:"nano" time_format:"unixnano"`
+	UTC     time.Time            `json:"utc" yaml:"utc" toml:"utc" form:"utc" time_format:"2006-01-02 15:04" time_utc:"1"`
+	Dates   []time.Time          `json:"dates" yaml:"dates" toml:"dates" form:"dates" time_format:"02/01/2006" time_utc:"1"`
+	ByName  map[string]time.Time `json:"by_name" yaml:"by_name" toml:"by_name" form:"-" time_format:"2006-01-02" time_utc:"1"`
+	Created time.Time            `json:"created" yaml:"created" toml:"created" form:"created"`
+}
+
+func TestBodyTimeTags(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	assert.NoError(t, err)
+
+	bodies := map[string]string{
+		MIMEJSON: `{"day": "2024-03-01", "unix": 1700000000, "nano": 1700000000123456789, "utc": "2024-03-01 08:30",
+			"dates": ["01/02/2024", "02/02/2024"], "by_name": {"launch": "2024-05-06"}, "created": "2024-03-01T08:30:00Z"}`,
+		MIMEYAML: "day: 2024-03-01\nunix: 1700000000\nnano: 1700000000123456789\nutc: 2024-03-01 08:30\n" +
+			"dates: [01/02/2024, 02/02/2024]\nby_name:\n  launch: 2024-05-06\ncreated: 2024-03-01T08:30:00Z\n",
+		MIMETOML: "day = 2024-03-01\nunix = 1700000000\nnano = 1700000000123456789\nutc = \"2024-03-01 08:30\"\n" +
+			"dates = [\"01/02/2024\", \"02/02/2024\"]\ncreated = 2024-03-01T08:30:00Z\n[by_name]\nlaunch = \"2024-05-06\"\n",
+	}
+	for contentType, body := range bodies {
+		var e timedEvent
+		req := requestWithBody("POST", "/", body)
+		req.Header.Set("Content-Type", contentType)
+		assert.NoError(t, Bind(req, &e), contentType)
+
+		assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, shanghai), e.Day, contentType)
+		assert.Equal(t, shanghai, e.Day.Location(), contentType)
+		assert.Equal(t, int64(1700000000), e.Unix.Unix(), contentType)
+		if assert.NotNil(t, e.Nano, contentType) {
+			assert.Equal(t, int64(1700000000123456789), e.Nano.UnixNano(), contentType)
+		}
+		assert.Equal(t, time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC), e.UTC, contentType)
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 2, 0, 0, 0, 0, time.UTC),
+		}, e.Dates, contentType)
+		assert.Equal(t, map[string]time.Time{"launch": time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)}, e.ByName, contentType)
+		assert.True(t, time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC).Equal(e.Created), contentType)
+	}
+
+	var form timedEvent
+	req := requestWithBody("POST", "/", "day=2024-03-01&unix=1700000000&utc=2024-03-01+08:30")
+	req.Header.Set("Content-Type", MIMEPOSTForm)
+	assert.NoError(t, Bind(req, &form))
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, shanghai), form.Day)
+	assert.Equal(t, time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC), form.UTC)
+}
+
+func TestBodyTimeTagsError(t *testing.T) {
+	var e timedEvent
+	err := bindBodyError(t, MIMEJSON, "{\n  \"day\": \"03/01/2024\"\n}", &e)
+	assert.Equal(t, 2, err.Line)
+	assert.Equal(t, "$.day", err.Path)
+	assert.Equal(t, "Day", err.Field)
+	var convErr *ConversionError
+	assert.ErrorAs(t, err, &convErr)
+
+	err = bindBodyError(t, MIMEYAML, "unix: 1\nday: soon\n", &e)
+	assert.Equal(t, 2, err.Line)
+	assert.Equal(t, 6, err.Column)
+	assert.Equal(t, "$.day", err.Path)
+	assert.Equal(t, "Day", err.Field)
+}
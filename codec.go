@@ -0,0 +1,83 @@
+package binding
+
+import (
+	stdjson "encoding/json"
+	"io"
+)
+
+// JSONDecoder reads and decodes JSON values from an input stream, as
+// encoding/json.Decoder does.
+type JSONDecoder interface {
+	UseNumber()
+	DisallowUnknownFields()
+	Decode(v interface{}) error
+}
+
+// JSONCodec is the JSON engine a Binding decodes JSON bodies, JSON Merge
+// Patch and JSON Patch bodies, and struct and map form values, with.
+type JSONCodec interface {
+	NewDecoder(r io.Reader) JSONDecoder
+	Unmarshal(data []byte, v interface{}) error
+	Marshal(v interface{}) ([]byte, error)
+}
+
+// The built-in JSON engines. JSONIter, the default, is jsoniter configured to
+// be compatible with the standard library, StdJSON is encoding/json itself.
+var (
+	StdJSON  JSONCodec = stdJSONCodec{}
+	JSONIter JSONCodec = jsoniterCodec{}
+)
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return stdjson.NewDecoder(r)
+}
+
+func (stdJSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return stdjson.Unmarshal(data, v)
+}
+
+func (stdJSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return stdjson.Marshal(v)
+}
+
+type jsoniterCodec struct{}
+
+func (jsoniterCodec) NewDecoder(r io.Reader) JSONDecoder {
+	return json.NewDecoder(r)
+}
+
+func (jsoniterCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsoniterCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// SetJSONCodec sets the JSON engine of the package level Binding.
+func SetJSONCodec(codec JSONCodec) {
+	defaultBinding.SetJSONCodec(codec)
+}
+
+// SetJSONCodec sets the JSON engine of b, nil restores JSONIter.
+//
+// Optional fields are the exception: Optional.UnmarshalJSON is called by the
+// engine itself, with no Binding to ask, and always decodes the wrapped value
+// with JSONIter.
+func (b *Binding) SetJSONCodec(codec JSONCodec) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.codec = codec
+}
+
+func (b *Binding) jsonCodec() JSONCodec {
+	b = b.orDefault()
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.codec == nil {
+		return JSONIter
+	}
+	return b.codec
+}
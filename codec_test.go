@@ -0,0 +1,75 @@
+package binding
+
+import (
+	stdjson "encoding/json"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingCodec struct {
+	JSONCodec
+	decoders, unmarshals, marshals int
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) JSONDecoder {
+	c.decoders++
+	return c.JSONCodec.NewDecoder(r)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v interface{}) error {
+	c.unmarshals++
+	return c.JSONCodec.Unmarshal(data, v)
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return c.JSONCodec.Marshal(v)
+}
+
+func TestJSONCodec(t *testing.T) {
+	codec := &countingCodec{JSONCodec: StdJSON}
+	b := New()
+	b.SetJSONCodec(codec)
+
+	var body struct {
+		Page int `json:"page"`
+	}
+	req := requestWithBody("POST", "/", `{"page": 2}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.NoError(t, b.Bind(req, &body))
+	assert.Equal(t, 2, body.Page)
+	assert.Equal(t, 1, codec.decoders)
+
+	var form struct {
+		Meta map[string]int `form:"meta"`
+	}
+	req = requestWithBody("POST", "/", `meta={"a":1}`)
+	req.Header.Set("Content-Type", MIMEPOSTForm)
+	assert.NoError(t, b.Bind(req, &form))
+	assert.Equal(t, map[string]int{"a": 1}, form.Meta)
+	assert.Equal(t, 1, codec.unmarshals)
+
+	user := newPatchUser()
+	req = requestWithBody("PATCH", "/", `{"name": "john"}`)
+	req.Header.Set("Content-Type", MIMEMergePatchJSON)
+	assert.NoError(t, b.Bind(req, &user))
+	assert.Equal(t, "john", user.Name)
+	assert.Equal(t, 3, codec.decoders)   // the patch and the target document
+	assert.Equal(t, 2, codec.marshals)   // the target and the patched document
+	assert.Equal(t, 2, codec.unmarshals) // into the target
+
+	b.SetJSONCodec(StdJSON)
+	req = requestWithBody("POST", "/", `{"page": "x"}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	var typeErr *stdjson.UnmarshalTypeError
+	assert.ErrorAs(t, b.Bind(req, &body), &typeErr)
+
+	req = requestWithBody("PATCH", "/", `{"age": "x"}`)
+	req.Header.Set("Content-Type", MIMEMergePatchJSON)
+	assert.ErrorAs(t, b.Bind(req, &user), &typeErr)
+
+	b.SetJSONCodec(nil)
+	assert.Equal(t, JSONIter, b.jsonCodec())
+}
@@ -0,0 +1,195 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// ConverterFunc converts a single parameter value into a value of the type it
+// is registered for. field is the struct field being bound, so a converter
+// can read its own tags.
+type ConverterFunc func(val string, field reflect.StructField) (interface{}, error)
+
+// ErrConverterResult converter returned a value of the wrong type
+var ErrConverterResult = errors.New("converter returned a value of the wrong type")
+
+// RegisterConverter registers fn for values of typ on the package level
+// Binding used by Bind, Form, Query, Header, URI and the other binders.
+func RegisterConverter(typ reflect.Type, fn ConverterFunc) {
+	defaultBinding.RegisterConverter(typ, fn)
+}
+
+// RegisterConverter registers fn for values of typ on b. Converters are
+// consulted before the built-in conversions, and a converter registered for
+// *T is used for fields of type T as well.
+func (b *Binding) RegisterConverter(typ reflect.Type, fn ConverterFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.converters == nil {
+		b.converters = make(map[reflect.Type]ConverterFunc)
+	}
+	b.converters[typ] = fn
+}
+
+// converter looks up the converter for typ, first in the converters of b,
+// then in the built-in converters.
+func (b *Binding) converter(typ reflect.Type) (ConverterFunc, bool) {
+	b = b.orDefault()
+
+	b.mu.RLock()
+	fn, ok := b.converters[typ]
+	b.mu.RUnlock()
+
+	if !ok {
+		fn, ok = builtinConverters[typ]
+	}
+	return fn, ok
+}
+
+func (b *Binding) hasConverter(typ reflect.Type) bool {
+	if _, ok := b.converter(typ); ok {
+		return true
+	}
+	_, ok := b.converter(reflect.PtrTo(typ))
+	return ok
+}
+
+// convert sets value with the converter registered for its type or a pointer
+// to its type, ok is false when there is no such converter.
+func (b *Binding) convert(val string, value reflect.Value, field reflect.StructField) (ok bool, err error) {
+	typ := value.Type()
+
+	fn, ok := b.converter(typ)
+	if !ok {
+		if fn, ok = b.converter(reflect.PtrTo(typ)); !ok {
+			return false, nil
+		}
+	}
+
+	v, err := fn(val, field)
+	if err != nil {
+		return true, err
+	}
+
+	rv := reflect.ValueOf(v)
+	switch {
+	case !rv.IsValid():
+		value.Set(reflect.Zero(typ))
+	case rv.Type().AssignableTo(typ):
+		value.Set(rv)
+	case rv.Kind() == reflect.Ptr && rv.Type().Elem().AssignableTo(typ):
+		if rv.IsNil() {
+			value.Set(reflect.Zero(typ))
+		} else {
+			value.Set(rv.Elem())
+		}
+	default:
+		return true, fmt.Errorf("%w: %s for %s", ErrConverterResult, rv.Type(), typ)
+	}
+	return true, nil
+}
+
+var builtinConverters = map[reflect.Type]ConverterFunc{
+	reflect.TypeOf(net.IP{}):         convertIP,
+	reflect.TypeOf(netip.Addr{}):     convertAddr,
+	reflect.TypeOf(netip.Prefix{}):   convertPrefix,
+	reflect.TypeOf(&url.URL{}):       convertURL,
+	reflect.TypeOf(&big.Int{}):       convertBigInt,
+	reflect.TypeOf(&big.Float{}):     convertBigFloat,
+	reflect.TypeOf(&big.Rat{}):       convertBigRat,
+	reflect.TypeOf(complex64(0)):     convertComplex64,
+	reflect.TypeOf(complex128(0)):    convertComplex128,
+	reflect.TypeOf(&time.Location{}): convertLocation,
+	reflect.TypeOf(&regexp.Regexp{}): convertRegexp,
+}
+
+func convertIP(val string, _ reflect.StructField) (interface{}, error) {
+	if val == "" {
+		return net.IP(nil), nil
+	}
+	ip := net.ParseIP(val)
+	if ip == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: val}
+	}
+	return ip, nil
+}
+
+func convertAddr(val string, _ reflect.StructField) (interface{}, error) {
+	if val == "" {
+		return netip.Addr{}, nil
+	}
+	return netip.ParseAddr(val)
+}
+
+func convertPrefix(val string, _ reflect.StructField) (interface{}, error) {
+	if val == "" {
+		return netip.Prefix{}, nil
+	}
+	return netip.ParsePrefix(val)
+}
+
+func convertURL(val string, _ reflect.StructField) (interface{}, error) {
+	return url.Parse(val)
+}
+
+func convertBigInt(val string, _ reflect.StructField) (interface{}, error) {
+	if val == "" {
+		return new(big.Int), nil
+	}
+	i, ok := new(big.Int).SetString(val, 0)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a valid integer", val)
+	}
+	return i, nil
+}
+
+func convertBigFloat(val string, _ reflect.StructField) (interface{}, error) {
+	if val == "" {
+		return new(big.Float), nil
+	}
+	f, _, err := big.ParseFloat(val, 10, 0, big.ToNearestEven)
+	return f, err
+}
+
+func convertBigRat(val string, _ reflect.StructField) (interface{}, error) {
+	if val == "" {
+		return new(big.Rat), nil
+	}
+	r, ok := new(big.Rat).SetString(val)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a valid rational number", val)
+	}
+	return r, nil
+}
+
+func convertComplex64(val string, _ reflect.StructField) (interface{}, error) {
+	if val == "" {
+		return complex64(0), nil
+	}
+	c, err := strconv.ParseComplex(val, 64)
+	return complex64(c), err
+}
+
+func convertComplex128(val string, _ reflect.StructField) (interface{}, error) {
+	if val == "" {
+		return complex128(0), nil
+	}
+	return strconv.ParseComplex(val, 128)
+}
+
+func convertLocation(val string, _ reflect.StructField) (interface{}, error) {
+	return time.LoadLocation(val)
+}
+
+func convertRegexp(val string, _ reflect.StructField) (interface{}, error) {
+	return regexp.Compile(val)
+}
@@ -0,0 +1,123 @@
+package binding
+
+import (
+	"errors"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMappingBuiltinConverters(t *testing.T) {
+	var s struct {
+		IP       net.IP         `form:"ip"`
+		Addr     netip.Addr     `form:"addr"`
+		Addrs    []netip.Addr   `form:"addrs"`
+		Prefix   netip.Prefix   `form:"prefix"`
+		URL      *url.URL       `form:"url"`
+		Int      *big.Int       `form:"int"`
+		Float    *big.Float     `form:"float"`
+		Rat      big.Rat        `form:"rat"`
+		C64      complex64      `form:"c64"`
+		C128     complex128     `form:"c128"`
+		Location *time.Location `form:"location"`
+		Regexp   *regexp.Regexp `form:"regexp"`
+	}
+
+	err := mapForm(&s, map[string][]string{
+		"ip":       {"192.168.0.1"},
+		"addr":     {"::1"},
+		"addrs":    {"10.0.0.1", "10.0.0.2"},
+		"prefix":   {"10.0.0.0/8"},
+		"url":      {"https://example.com/path?q=1"},
+		"int":      {"0x10"},
+		"float":    {"1.5"},
+		"rat":      {"3/4"},
+		"c64":      {"1+2i"},
+		"c128":     {"(3-4i)"},
+		"location": {"Asia/Shanghai"},
+		"regexp":   {"^a+$"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "192.168.0.1", s.IP.String())
+	assert.Equal(t, netip.MustParseAddr("::1"), s.Addr)
+	assert.Equal(t, []netip.Addr{netip.MustParseAddr("10.0.0.1"), netip.MustParseAddr("10.0.0.2")}, s.Addrs)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), s.Prefix)
+	assert.Equal(t, "example.com", s.URL.Host)
+	assert.Equal(t, int64(16), s.Int.Int64())
+	assert.Equal(t, "1.5", s.Float.String())
+	assert.Equal(t, "3/4", s.Rat.String())
+	assert.Equal(t, complex64(1+2i), s.C64)
+	assert.Equal(t, complex128(3-4i), s.C128)
+	assert.Equal(t, "Asia/Shanghai", s.Location.String())
+	assert.True(t, s.Regexp.MatchString("aaa"))
+
+	for key, val := range map[string]string{
+		"ip":       "999.0.0.1",
+		"addr":     "nope",
+		"int":      "1.5",
+		"rat":      "x",
+		"c64":      "1+",
+		"location": "Nowhere/Nothing",
+		"regexp":   "(",
+	} {
+		err = mapForm(&s, map[string][]string{key: {val}})
+		assert.Error(t, err, key)
+	}
+}
+
+type testColor struct {
+	R, G, B uint8
+}
+
+func convertTestColor(val string, field reflect.StructField) (interface{}, error) {
+	switch strings.ToLower(val) {
+	case "red":
+		return testColor{R: 255}, nil
+	case "blue":
+		return &testColor{B: 255}, nil
+	}
+	return nil, errors.New("unknown color")
+}
+
+func TestBindingRegisterConverter(t *testing.T) {
+	type colors struct {
+		Color  testColor   `query:"color"`
+		Ptr    *testColor  `query:"ptr"`
+		Colors []testColor `query:"colors"`
+	}
+
+	b := New()
+	b.RegisterConverter(reflect.TypeOf(testColor{}), convertTestColor)
+
+	var s colors
+	req := requestWithBody("GET", "/?color=red&ptr=blue&colors=blue&colors=red", "")
+	err := b.Bind(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, testColor{R: 255}, s.Color)
+	assert.Equal(t, &testColor{B: 255}, s.Ptr)
+	assert.Equal(t, []testColor{{B: 255}, {R: 255}}, s.Colors)
+
+	req = requestWithBody("GET", "/?color=green", "")
+	err = b.Bind(req, &s)
+	assert.EqualError(t, err, "unknown color")
+
+	// converters are scoped to their Binding
+	s = colors{}
+	req = requestWithBody("GET", "/?color=red", "")
+	err = Bind(req, &s)
+	assert.Error(t, err)
+
+	b.RegisterConverter(reflect.TypeOf(testColor{}), func(string, reflect.StructField) (interface{}, error) {
+		return 1, nil
+	})
+	err = b.Bind(req, &s)
+	assert.ErrorIs(t, err, ErrConverterResult)
+}
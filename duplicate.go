@@ -0,0 +1,99 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrDuplicateParam parameter of a scalar field is given more than once
+var ErrDuplicateParam = errors.New("duplicate parameter")
+
+// DuplicateParamError is returned under the DuplicateError policy when a
+// scalar field or map[string]string entry is given several values. It wraps
+// ErrDuplicateParam.
+type DuplicateParamError struct {
+	Source string // "query", "form", "header" or "uri"
+	Key    string
+	Values []string
+}
+
+func (e *DuplicateParamError) Error() string {
+	return fmt.Sprintf("%s: %s %q given %d times", ErrDuplicateParam, e.Source, e.Key, len(e.Values))
+}
+
+func (e *DuplicateParamError) Unwrap() error {
+	return ErrDuplicateParam
+}
+
+// DuplicatePolicy decides which value a scalar field takes from a parameter
+// given several times, such as ?page=1&page=2. Slice and array fields take
+// every value whatever the policy.
+type DuplicatePolicy int
+
+const (
+	// DuplicateDefault is the historical behaviour, fields take the first
+	// value and map entries the last, whatever the map: a map[string]string
+	// or typed map target, a map field, or a prefix map such as query:"m_*".
+	DuplicateDefault DuplicatePolicy = iota
+	// DuplicateFirst takes the first value.
+	DuplicateFirst
+	// DuplicateLast takes the last value.
+	DuplicateLast
+	// DuplicateError rejects the request with a *DuplicateParamError.
+	DuplicateError
+)
+
+var duplicatePolicies = map[string]DuplicatePolicy{
+	"first": DuplicateFirst,
+	"last":  DuplicateLast,
+	"error": DuplicateError,
+}
+
+// SetDuplicatePolicy sets the duplicate parameter policy of the package
+// level Binding. Fields override it with the duplicate tag option:
+//
+//	Page int `query:"page,duplicate=error"`
+func SetDuplicatePolicy(policy DuplicatePolicy) {
+	defaultBinding.SetDuplicatePolicy(policy)
+}
+
+// SetDuplicatePolicy sets the duplicate parameter policy of b, applied to
+// the query, form, header and uri sources and to map targets alike.
+func (b *Binding) SetDuplicatePolicy(policy DuplicatePolicy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.duplicates = policy
+}
+
+func (b *Binding) duplicatePolicy() DuplicatePolicy {
+	b = b.orDefault()
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.duplicates
+}
+
+// mapEntry returns opt for setting the entries of a map, which take the last
+// value under the DuplicateDefault policy.
+func (opt setOptions) mapEntry() setOptions {
+	if opt.duplicates == DuplicateDefault {
+		opt.duplicates = DuplicateLast
+	}
+	return opt
+}
+
+// pick returns the value a scalar takes from vs, def is the value the
+// DuplicateDefault policy takes.
+func (policy DuplicatePolicy) pick(source, key string, vs []string, def DuplicatePolicy) (string, error) {
+	if policy == DuplicateDefault {
+		policy = def
+	}
+	switch {
+	case len(vs) == 0:
+		return "", nil
+	case len(vs) > 1 && policy == DuplicateError:
+		return "", &DuplicateParamError{Source: source, Key: key, Values: vs}
+	case policy == DuplicateLast:
+		return vs[len(vs)-1], nil
+	}
+	return vs[0], nil
+}
@@ -0,0 +1,102 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindingDuplicatePolicy(t *testing.T) {
+	type page struct {
+		Page  int      `query:"page" header:"X-Page" uri:"page"`
+		Sort  string   `query:"sort,duplicate=last"`
+		Size  int      `query:"size,duplicate=error"`
+		Tags  []string `query:"tags"`
+		Limit int      `query:"limit,duplicate=first"`
+	}
+
+	var s page
+	req := requestWithBody("GET", "/?page=1&page=2&sort=a&sort=b&tags=x&tags=y&limit=5&limit=6", "")
+	assert.NoError(t, Query.Bind(req, &s))
+	assert.Equal(t, page{Page: 1, Sort: "b", Tags: []string{"x", "y"}, Limit: 5}, s)
+
+	req = requestWithBody("GET", "/?size=1&size=2", "")
+	err := Query.Bind(req, &s)
+	var dupErr *DuplicateParamError
+	if assert.ErrorAs(t, err, &dupErr) {
+		assert.Equal(t, &DuplicateParamError{Source: "query", Key: "size", Values: []string{"1", "2"}}, dupErr)
+	}
+	assert.ErrorIs(t, err, ErrDuplicateParam)
+	assert.EqualError(t, err, `duplicate parameter: query "size" given 2 times`)
+
+	b := New()
+	b.SetDuplicatePolicy(DuplicateLast)
+	s = page{}
+	req = requestWithBody("GET", "/?page=1&page=2&limit=5&limit=6", "")
+	assert.NoError(t, b.Bind(req, &s))
+	assert.Equal(t, 2, s.Page)
+	assert.Equal(t, 5, s.Limit)
+
+	b.SetDuplicatePolicy(DuplicateError)
+	req = requestWithBody("GET", "/?page=1&page=2", "")
+	assert.ErrorIs(t, b.Bind(req, &s), ErrDuplicateParam)
+
+	req = requestWithBody("GET", "/", "")
+	req.Header.Add("X-Page", "1")
+	req.Header.Add("X-Page", "2")
+	err = b.BindWith(req, &s, Header)
+	if assert.ErrorAs(t, err, &dupErr) {
+		assert.Equal(t, "header", dupErr.Source)
+		assert.Equal(t, "X-Page", dupErr.Key)
+	}
+
+	err = uriBinding{binding: b}.BindURI(map[string][]string{"page": {"1", "2"}}, &s)
+	assert.ErrorIs(t, err, ErrDuplicateParam)
+
+	req = requestWithBody("POST", "/", "page=1&page=2")
+	req.Header.Set("Content-Type", MIMEPOSTForm)
+	var form struct {
+		Page int `form:"page"`
+	}
+	assert.ErrorIs(t, b.BindWith(req, &form, Form), ErrDuplicateParam)
+
+	m := map[string]string{}
+	req = requestWithBody("GET", "/?a=1&a=2", "")
+	assert.ErrorIs(t, b.BindWith(req, &m, Query), ErrDuplicateParam)
+	b.SetDuplicatePolicy(DuplicateFirst)
+	assert.NoError(t, b.BindWith(req, &m, Query))
+	assert.Equal(t, "1", m["a"])
+
+	var invalid struct {
+		Page int `query:"page,duplicate=random"`
+	}
+	assert.Error(t, Query.Bind(req, &invalid))
+}
+
+func TestDuplicateDefaultMaps(t *testing.T) {
+	req := requestWithBody("GET", "/?a=1&a=2&m_a=1&m_a=2&f[a]=1&f[a]=2&page=1&page=2", "")
+
+	m := map[string]string{}
+	assert.NoError(t, New().BindWith(req, &m, Query))
+	assert.Equal(t, "2", m["a"])
+
+	ints := map[string]int{}
+	assert.NoError(t, New().BindWith(req, &ints, Query))
+	assert.Equal(t, 2, ints["a"])
+
+	var s struct {
+		Page   int               `query:"page"`
+		Prefix map[string]string `query:"m_*"`
+		Nested map[string]int    `query:"f"`
+	}
+	assert.NoError(t, New().BindWith(req, &s, Query))
+	assert.Equal(t, 1, s.Page)
+	assert.Equal(t, map[string]string{"a": "2"}, s.Prefix)
+	assert.Equal(t, map[string]int{"a": 2}, s.Nested)
+
+	b := New()
+	b.SetDuplicatePolicy(DuplicateFirst)
+	assert.NoError(t, b.BindWith(req, &s, Query))
+	assert.Equal(t, map[string]string{"a": "1"}, s.Prefix)
+	assert.Equal(t, map[string]int{"a": 1}, s.Nested)
+}
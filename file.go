@@ -0,0 +1,134 @@
+package binding
+
+import (
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"reflect"
+)
+
+// File is an uploaded file of a multipart form. Besides File, file fields
+// bind into multipart.FileHeader, []byte and string, which receive the
+// content, io.ReadCloser, which the caller must close, and slices and arrays
+// of them.
+type File struct {
+	// Name is the sanitised file name sent by the client.
+	Name string
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// ContentType is the content type the client declared for the file.
+	ContentType string
+
+	// DetectedContentType is the content type http.DetectContentType
+	// sniffed from the first 512 bytes of the file.
+	DetectedContentType string
+
+	// Header holds the MIME headers of the part.
+	Header textproto.MIMEHeader
+
+	// Hash is the digest of the file computed by the hash tag option, such
+	// as form:"avatar,hash=sha256", as the file is read. It is set once a
+	// reader returned by Open reaches the end of the file, nil until then
+	// and without the option.
+	Hash []byte
+
+	fileHeader *multipart.FileHeader
+	newHash    func() hash.Hash
+}
+
+// Open opens the file for reading.
+func (f *File) Open() (multipart.File, error) {
+	file, err := f.fileHeader.Open()
+	if err != nil || f.newHash == nil {
+		return file, err
+	}
+	h := f.newHash()
+	return &hashingFile{File: file, r: io.TeeReader(file, h), h: h, f: f}, nil
+}
+
+// hashingFile computes the digest of a File as it is read, and sets Hash at
+// the end of the file. Seeking anywhere but the start gives up the digest.
+type hashingFile struct {
+	multipart.File
+	r io.Reader
+	h hash.Hash // nil once the digest is set or given up
+	f *File
+}
+
+func (hf *hashingFile) Read(p []byte) (int, error) {
+	n, err := hf.r.Read(p)
+	if err == io.EOF && hf.h != nil {
+		hf.f.Hash, hf.h = hf.h.Sum(nil), nil
+	}
+	return n, err
+}
+
+func (hf *hashingFile) Seek(offset int64, whence int) (int64, error) {
+	pos, err := hf.File.Seek(offset, whence)
+	if hf.h != nil {
+		if err == nil && pos == 0 {
+			hf.h.Reset()
+		} else {
+			hf.h = nil
+		}
+	}
+	return pos, err
+}
+
+var (
+	fileType        = reflect.TypeOf(File{})
+	bytesType       = reflect.TypeOf([]byte(nil))
+	readCloserType  = reflect.TypeOf((*io.ReadCloser)(nil)).Elem()
+	fileHeaderType  = reflect.TypeOf(multipart.FileHeader{})
+	fileHeaderPType = reflect.TypeOf((*multipart.FileHeader)(nil))
+)
+
+// newFile reads the start of fh to sniff its content type.
+func newFile(fh *multipart.FileHeader, opt *fileOptions) (*File, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var buf [512]byte
+	n, err := io.ReadFull(f, buf[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	file := &File{
+		Name:                fh.Filename,
+		Size:                fh.Size,
+		ContentType:         fh.Header.Get("Content-Type"),
+		DetectedContentType: http.DetectContentType(buf[:n]),
+		Header:              fh.Header,
+		fileHeader:          fh,
+	}
+	if opt != nil {
+		file.newHash = opt.newHash
+	}
+	return file, nil
+}
+
+// readFile returns the content of fh, reading no more than the max_size
+// option allows.
+func readFile(fh *multipart.FileHeader, opt *fileOptions) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if opt == nil || opt.maxSize <= 0 {
+		return io.ReadAll(f)
+	}
+	data, err := io.ReadAll(io.LimitReader(f, opt.maxSize+1))
+	if err == nil && int64(len(data)) > opt.maxSize {
+		return nil, &FileError{Filename: fh.Filename, Err: ErrFileTooLarge}
+	}
+	return data, err
+}
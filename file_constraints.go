@@ -0,0 +1,246 @@
+package binding
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// ErrFileTooLarge file is larger than the max_size tag option
+	ErrFileTooLarge = errors.New("file too large")
+
+	// ErrFilesTooLarge files are larger in total than the max_total tag option
+	ErrFilesTooLarge = errors.New("files too large in total")
+
+	// ErrTooManyFiles more files than the max_files tag option
+	ErrTooManyFiles = errors.New("too many files")
+
+	// ErrFileType file content type is not listed in the accept tag option
+	ErrFileType = errors.New("file type not accepted")
+
+	// ErrInvalidFileConstraint tag option of a file field can not be parsed
+	ErrInvalidFileConstraint = errors.New("invalid file constraint")
+)
+
+// FileError reports an uploaded file which violates the constraints of its
+// field. Err is one of ErrFileTooLarge, ErrFilesTooLarge, ErrTooManyFiles and
+// ErrFileType.
+type FileError struct {
+	Field    string // form key of the field
+	Filename string // sanitised file name, empty for count and total limits
+	Err      error
+}
+
+func (e *FileError) Error() string {
+	if e.Filename == "" {
+		return fmt.Sprintf("field %q: %v", e.Field, e.Err)
+	}
+	return fmt.Sprintf("field %q: file %q: %v", e.Field, e.Filename, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// fileOptions are the tag options of a file field:
+//
+//	form:"avatar,max_size=5MB,max_files=3,max_total=10MB,accept=image/png|image/jpeg,hash=sha256"
+type fileOptions struct {
+	maxSize  int64
+	maxTotal int64
+	maxFiles int
+	accept   []string
+	newHash  func() hash.Hash
+}
+
+// set parses the file constraint tag option key.
+func (c *fileOptions) set(key, value string) error {
+	var err error
+	switch key {
+	case "max_size":
+		c.maxSize, err = parseByteSize(value)
+	case "max_total":
+		c.maxTotal, err = parseByteSize(value)
+	case "max_files":
+		c.maxFiles, err = strconv.Atoi(value)
+	case "accept":
+		c.accept = strings.Split(value, "|")
+	case "hash":
+		var ok bool
+		if c.newHash, ok = fileHashes[strings.ToLower(value)]; !ok {
+			err = ErrInvalidFileConstraint
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s=%s", ErrInvalidFileConstraint, key, value)
+	}
+	return nil
+}
+
+var fileHashes = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha224": sha256.New224,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+	{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+	{"B", 1},
+}
+
+// parseByteSize parses sizes such as "512", "100KB" or "5MB", units are
+// binary, 1KB is 1024 bytes.
+func parseByteSize(s string) (int64, error) {
+	unit := int64(1)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(strings.ToUpper(s), strings.ToUpper(u.suffix)) {
+			s, unit = strings.TrimSpace(s[:len(s)-len(u.suffix)]), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, ErrInvalidFileConstraint
+	}
+	return n * unit, nil
+}
+
+// check returns a FileError for the first file of the field key which
+// violates the constraints.
+func (c *fileOptions) check(key string, files []*multipart.FileHeader) error {
+	if c == nil {
+		return nil
+	}
+	if c.maxFiles > 0 && len(files) > c.maxFiles {
+		return &FileError{Field: key, Err: ErrTooManyFiles}
+	}
+
+	var total int64
+	for _, fh := range files {
+		if c.maxSize > 0 && fh.Size > c.maxSize {
+			return &FileError{Field: key, Filename: fh.Filename, Err: ErrFileTooLarge}
+		}
+		total += fh.Size
+
+		if len(c.accept) > 0 {
+			ok, err := c.accepts(fh)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return &FileError{Field: key, Filename: fh.Filename, Err: ErrFileType}
+			}
+		}
+	}
+	if c.maxTotal > 0 && total > c.maxTotal {
+		return &FileError{Field: key, Err: ErrFilesTooLarge}
+	}
+	return nil
+}
+
+// checkTarget returns an error when the options do not apply to a value of
+// t: the hash option needs File fields, the only ones with room for the
+// digest.
+func (c *fileOptions) checkTarget(key string, t reflect.Type) error {
+	if c == nil || c.newHash == nil || isHashTarget(t) {
+		return nil
+	}
+	return fmt.Errorf("%w: field %q: hash needs a File field, not %s", ErrInvalidFileConstraint, key, t)
+}
+
+func isHashTarget(t reflect.Type) bool {
+	switch {
+	case t == fileType || t == reflect.PtrTo(fileType):
+		return true
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return isHashTarget(t.Elem())
+	}
+	return false
+}
+
+// accepts checks both the content type the client declared, unless it is the
+// generic application/octet-stream, and the one sniffed from the content.
+func (c *fileOptions) accepts(fh *multipart.FileHeader) (bool, error) {
+	declared := filterFlags(fh.Header.Get("Content-Type"))
+	if declared != "" && declared != "application/octet-stream" && !c.acceptsType(declared) {
+		return false, nil
+	}
+
+	sniffed, err := sniffContentType(fh)
+	if err != nil {
+		return false, err
+	}
+	return c.acceptsType(sniffed), nil
+}
+
+// acceptsType matches contentType against the accepted types, which may be
+// wildcards such as "image/*".
+func (c *fileOptions) acceptsType(contentType string) bool {
+	for _, accept := range c.accept {
+		accept = strings.TrimSpace(accept)
+		if strings.EqualFold(accept, contentType) || accept == "*/*" {
+			return true
+		}
+		if prefix := strings.TrimSuffix(accept, "*"); prefix != accept &&
+			strings.HasPrefix(strings.ToLower(contentType), strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffContentType detects the content type of the first 512 bytes of the
+// file, without parameters such as the charset.
+func sniffContentType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var buf [512]byte
+	n, err := io.ReadFull(f, buf[:])
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return filterFlags(http.DetectContentType(buf[:n])), nil
+}
+
+// sanitizeFilename reduces a client supplied file name to its base name, so
+// names such as "../../etc/passwd" or "..\\boot.ini" can not escape the
+// directory they are saved to. Control characters are dropped, names which
+// are only dots or separators become empty.
+func sanitizeFilename(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if strings.Trim(name, "./") == "" {
+		return ""
+	}
+	return name
+}
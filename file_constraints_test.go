@@ -0,0 +1,129 @@
+package binding
+
+import (
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testPNG = []byte("\x89PNG\x0D\x0A\x1A\x0A\x00\x00\x00\x0DIHDR")
+
+// createRequestTypedFiles is createRequestMultipartFiles with a declared
+// content type for every file.
+func createRequestTypedFiles(t *testing.T, contentType string, files ...testFile) *http.Request {
+	parts := make([]testPart, len(files))
+	for i, file := range files {
+		parts[i] = testPart{Name: file.Fieldname, Filename: file.Filename, ContentType: contentType, Content: string(file.Content)}
+	}
+	return createRequestTypedParts(t, MIMEMultipartPOSTForm, parts...)
+}
+
+type avatarForm struct {
+	Avatar []*multipart.FileHeader `form:"avatar,max_size=16B,max_files=2,max_total=30B,accept=image/png|image/jpeg"`
+}
+
+func TestFileConstraints(t *testing.T) {
+	var s avatarForm
+	req := createRequestTypedFiles(t, "image/png", testFile{"avatar", "a.png", testPNG})
+	assert.NoError(t, FormMultipart.Bind(req, &s))
+	assert.Len(t, s.Avatar, 1)
+
+	// application/octet-stream is not checked, the sniffed type is
+	req = createRequestMultipartFiles(t, testFile{"avatar", "a.png", testPNG})
+	assert.NoError(t, FormMultipart.Bind(req, &s))
+
+	for _, tt := range []struct {
+		req *http.Request
+		err *FileError
+	}{
+		{
+			createRequestTypedFiles(t, "image/png", testFile{"avatar", "big.png", append(testPNG, 0)}),
+			&FileError{Field: "avatar", Filename: "big.png", Err: ErrFileTooLarge},
+		},
+		{
+			createRequestTypedFiles(t, "image/png",
+				testFile{"avatar", "a.png", testPNG},
+				testFile{"avatar", "b.png", testPNG},
+				testFile{"avatar", "c.png", testPNG}),
+			&FileError{Field: "avatar", Err: ErrTooManyFiles},
+		},
+		{
+			createRequestTypedFiles(t, "image/png",
+				testFile{"avatar", "a.png", testPNG},
+				testFile{"avatar", "b.png", testPNG}),
+			&FileError{Field: "avatar", Err: ErrFilesTooLarge},
+		},
+		{
+			createRequestTypedFiles(t, "image/gif", testFile{"avatar", "a.png", testPNG}),
+			&FileError{Field: "avatar", Filename: "a.png", Err: ErrFileType},
+		},
+		{
+			createRequestTypedFiles(t, "image/png", testFile{"avatar", "a.png", []byte("<html>")}),
+			&FileError{Field: "avatar", Filename: "a.png", Err: ErrFileType},
+		},
+	} {
+		var fileErr *FileError
+		err := FormMultipart.Bind(tt.req, &s)
+		if assert.ErrorAs(t, err, &fileErr) {
+			assert.Equal(t, tt.err, fileErr)
+		}
+		assert.ErrorIs(t, err, tt.err.Err)
+	}
+
+	var invalid struct {
+		File *multipart.FileHeader `form:"file,max_size=lots"`
+	}
+	req = createRequestMultipartFiles(t, testFile{"file", "a.txt", []byte("a")})
+	assert.ErrorIs(t, FormMultipart.Bind(req, &invalid), ErrInvalidFileConstraint)
+}
+
+func TestFileConstraintsWildcard(t *testing.T) {
+	var s struct {
+		Image *multipart.FileHeader `form:"image,accept=image/*"`
+	}
+	req := createRequestTypedFiles(t, "image/png", testFile{"image", "a.png", testPNG})
+	assert.NoError(t, FormMultipart.Bind(req, &s))
+
+	req = createRequestTypedFiles(t, "text/plain", testFile{"image", "a.txt", []byte("text")})
+	assert.ErrorIs(t, FormMultipart.Bind(req, &s), ErrFileType)
+}
+
+func TestParseByteSize(t *testing.T) {
+	for s, want := range map[string]int64{
+		"512": 512, "10B": 10, "1KB": 1 << 10, "5MB": 5 << 20, "2GiB": 2 << 30, "3 mb": 3 << 20, "1k": 1 << 10,
+	} {
+		n, err := parseByteSize(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, want, n, s)
+	}
+	for _, s := range []string{"", "MB", "-1KB", "1TB", "x"} {
+		_, err := parseByteSize(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	for name, want := range map[string]string{
+		"avatar.png":          "avatar.png",
+		"../../etc/passwd":    "passwd",
+		`..\..\boot.ini`:      "boot.ini",
+		"/abs/path/file.txt":  "file.txt",
+		"..":                  "",
+		"../":                 "",
+		"./.":                 "",
+		"evil\x00name\n.txt":  "evilname.txt",
+		"dir/.hidden":         ".hidden",
+		"C:\\Users\\me\\a.md": "a.md",
+	} {
+		assert.Equal(t, want, sanitizeFilename(name), name)
+	}
+
+	var s struct {
+		File *multipart.FileHeader `form:"file"`
+	}
+	req := createRequestTypedFiles(t, "text/plain", testFile{"file", `..\..\secret.txt`, []byte("a")})
+	assert.NoError(t, FormMultipart.Bind(req, &s))
+	assert.Equal(t, "secret.txt", s.File.Filename)
+}
@@ -7,23 +7,47 @@ import (
 
 const defaultMemory = 32 << 20
 
-type formBinder struct{}
+type formBinder struct {
+	binding *Binding
+	rec     *recorder
+}
+
+func (formBinder) withBinding(b *Binding, rec *recorder) Binder {
+	return formBinder{b, rec}
+}
 
-func (formBinder) Bind(req *http.Request, obj interface{}) error {
+func (fb formBinder) Bind(req *http.Request, obj interface{}) error {
 	if err := req.ParseForm(); err != nil {
 		return err
 	}
 	if err := req.ParseMultipartForm(defaultMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
 		return err
 	}
-	return mapForm(obj, req.Form)
+	fb.rec.input("query", req.URL.Query())
+	fb.rec.input("form", req.PostForm)
+	if req.MultipartForm != nil {
+		fb.rec.input("form", req.MultipartForm.Value)
+	}
+	return fb.binding.mapFormByTag(obj, req.Form, "form", fb.rec)
 }
 
-type formMultipartBinder struct{}
+type formMultipartBinder struct {
+	binding *Binding
+	rec     *recorder
+}
+
+func (formMultipartBinder) withBinding(b *Binding, rec *recorder) Binder {
+	return formMultipartBinder{b, rec}
+}
 
-func (formMultipartBinder) Bind(req *http.Request, obj interface{}) error {
-	if err := req.ParseMultipartForm(defaultMemory); err != nil {
+func (fb formMultipartBinder) Bind(req *http.Request, obj interface{}) error {
+	partHeaders, err := parseMultipartForm(req, defaultMemory, obj)
+	if err != nil {
 		return err
 	}
-	return mappingByPtr(obj, (*multipartRequest)(req), "form")
+	fb.rec.input("form", req.MultipartForm.Value)
+	for key, files := range req.MultipartForm.File {
+		fb.rec.input("form", map[string][]string{key: make([]string, len(files))})
+	}
+	return fb.binding.mappingByPtr(obj, &multipartRequest{req, partHeaders}, "form", fb.rec)
 }
@@ -5,8 +5,10 @@
 package binding
 
 import (
+	"encoding"
 	"errors"
 	"fmt"
+	"net/textproto"
 	"reflect"
 	"strconv"
 	"strings"
@@ -23,6 +25,34 @@ var (
 	ErrConvertToMapString = errors.New("can not convert to map of strings")
 )
 
+// ConversionError reports a map entry whose values do not convert to the
+// element type of the map.
+type ConversionError struct {
+	Key    string // key on the wire, such as "meta[size]"
+	Values []string
+	Type   reflect.Type
+	Err    error
+}
+
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("can not convert %s %q to %s: %v", e.Key, e.Values, e.Type, e.Err)
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// conversionError wraps err in a ConversionError, unless it already is one
+// or reports something other than a failed conversion.
+func conversionError(key string, vs []string, t reflect.Type, err error) error {
+	var convErr *ConversionError
+	var dupErr *DuplicateParamError
+	if errors.As(err, &convErr) || errors.As(err, &dupErr) || errors.Is(err, ErrUnknownParams) {
+		return err
+	}
+	return &ConversionError{Key: key, Values: vs, Type: t, Err: err}
+}
+
 func mapForm(ptr interface{}, form map[string][]string) error {
 	return mapFormByTag(ptr, form, "form")
 }
@@ -33,7 +63,13 @@ func mapFormWithTag(ptr interface{}, form map[string][]string, tag string) error
 
 var emptyField = reflect.StructField{}
 
+var timeType = reflect.TypeOf(time.Time{})
+
 func mapFormByTag(ptr interface{}, form map[string][]string, tag string) error {
+	return defaultBinding.mapFormByTag(ptr, form, tag, nil)
+}
+
+func (b *Binding) mapFormByTag(ptr interface{}, form map[string][]string, tag string, rec *recorder) error {
 	// Check if ptr is a map
 	ptrVal := reflect.ValueOf(ptr)
 	var pointed interface{}
@@ -46,12 +82,28 @@ func mapFormByTag(ptr interface{}, form map[string][]string, tag string) error {
 		if pointed != nil {
 			ptr = pointed
 		}
-		return setFormMap(ptr, form)
+		switch ptr.(type) {
+		case map[string]string, map[string][]string:
+			return setFormMap(ptr, form, tag, b.duplicatePolicy())
+		}
+		opt := setOptions{tag: tag, binding: b.orDefault(), rec: rec, duplicates: b.duplicatePolicy()}
+		return setTypedMap(ptrVal, form, opt)
 	}
 
-	return mappingByPtr(ptr, formSource(form), tag)
+	return b.mappingByPtr(ptr, formSource(form), tag, rec)
+}
+
+// Unmarshaler is the interface implemented by types that can unmarshal
+// themselves from the raw values of a form, query, header or uri parameter.
+type Unmarshaler interface {
+	UnmarshalParams(values []string) error
 }
 
+var (
+	unmarshalerType     = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
 // setter tries to set value on a walking by fields of a struct
 type setter interface {
 	TrySet(value reflect.Value, field reflect.StructField, key string, opt setOptions) (isSet bool, err error)
@@ -67,12 +119,26 @@ func (form formSource) TrySet(value reflect.Value, field reflect.StructField, ta
 }
 
 func mappingByPtr(ptr interface{}, setter setter, tag string) error {
-	_, err := mapping(reflect.ValueOf(ptr), emptyField, setter, tag)
-	return err
+	return defaultBinding.mappingByPtr(ptr, setter, tag, nil)
+}
+
+func (b *Binding) mappingByPtr(ptr interface{}, setter setter, tag string, rec *recorder) error {
+	walk := &mappingWalk{used: make(map[string]bool)}
+	opt := setOptions{tag: tag, binding: b.orDefault(), rec: rec, walk: walk}
+	if _, err := mapping(reflect.ValueOf(ptr), emptyField, setter, opt); err != nil {
+		return err
+	}
+	walk.finish()
+	return nil
 }
 
-func mapping(value reflect.Value, field reflect.StructField, setter setter, tag string) (bool, error) {
-	if field.Tag.Get(tag) == "-" { // just ignoring this field
+// mapping walks value and sets it from setter, opt carries the tag, the
+// Binding and the recorder of the whole walk, and the path of value.
+func mapping(value reflect.Value, field reflect.StructField, setter setter, opt setOptions) (bool, error) {
+	if field.Tag.Get(opt.tag) == "-" { // just ignoring this field
+		return false, nil
+	}
+	if _, ok := field.Tag.Lookup("body"); ok { // the body binder sets this field
 		return false, nil
 	}
 
@@ -85,7 +151,7 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 			isNew = true
 			vPtr = reflect.New(value.Type().Elem())
 		}
-		isSet, err := mapping(vPtr.Elem(), field, setter, tag)
+		isSet, err := mapping(vPtr.Elem(), field, setter, opt)
 		if err != nil {
 			return false, err
 		}
@@ -96,7 +162,7 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 	}
 
 	if vKind != reflect.Struct || !field.Anonymous {
-		ok, err := tryToSetValue(value, field, setter, tag)
+		ok, err := tryToSetValue(value, field, setter, opt)
 		if err != nil {
 			return false, err
 		}
@@ -114,7 +180,11 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 			if sf.PkgPath != "" && !sf.Anonymous { // unexported
 				continue
 			}
-			ok, err := mapping(value.Field(i), sf, setter, tag)
+			sfOpt := opt
+			if !sf.Anonymous {
+				sfOpt.path = joinFieldPath(opt.path, sf.Name)
+			}
+			ok, err := mapping(value.Field(i), sf, setter, sfOpt)
 			if err != nil {
 				return false, err
 			}
@@ -126,15 +196,26 @@ func mapping(value reflect.Value, field reflect.StructField, setter setter, tag
 }
 
 type setOptions struct {
+	tag      string
+	binding  *Binding
+	rec      *recorder
+	walk     *mappingWalk
+	path     string
+	wireKeys map[string]string
+
 	isDefaultExists bool
 	defaultValue    string
+	omitEmpty       bool
+	duplicates      DuplicatePolicy
+	files           *fileOptions
 }
 
-func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter, tag string) (bool, error) {
+func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter, opt setOptions) (bool, error) {
 	var tagValue string
-	var setOpt setOptions
+	var setOpt = setOptions{tag: opt.tag, binding: opt.binding, rec: opt.rec, walk: opt.walk, path: opt.path, wireKeys: opt.wireKeys}
+	setOpt.duplicates = opt.binding.duplicatePolicy()
 
-	tagValue = field.Tag.Get(tag)
+	tagValue = field.Tag.Get(opt.tag)
 	tagValue, opts := head(tagValue, ",")
 
 	if tagValue == "" { // default value is FieldName
@@ -144,13 +225,32 @@ func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter
 		return false, nil
 	}
 
-	var opt string
+	var o string
 	for len(opts) > 0 {
-		opt, opts = head(opts, ",")
+		o, opts = head(opts, ",")
+		if o == "remaining" {
+			return opt.walk.setRemaining(value, setter, setOpt)
+		}
 
-		if k, v := head(opt, "="); k == "default" {
+		switch k, v := head(o, "="); k {
+		case "default":
 			setOpt.isDefaultExists = true
 			setOpt.defaultValue = v
+		case "omitempty":
+			setOpt.omitEmpty = true
+		case "duplicate":
+			policy, ok := duplicatePolicies[v]
+			if !ok {
+				return false, fmt.Errorf("unknown duplicate policy %q", v)
+			}
+			setOpt.duplicates = policy
+		case "max_size", "max_total", "max_files", "accept", "hash":
+			if setOpt.files == nil {
+				setOpt.files = new(fileOptions)
+			}
+			if err := setOpt.files.set(k, v); err != nil {
+				return false, err
+			}
 		}
 	}
 
@@ -158,17 +258,63 @@ func tryToSetValue(value reflect.Value, field reflect.StructField, setter setter
 }
 
 func setByForm(value reflect.Value, field reflect.StructField, form map[string][]string, tagValue string, opt setOptions) (isSet bool, err error) {
+	if prefix := strings.TrimSuffix(tagValue, "*"); prefix != tagValue &&
+		value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String {
+		return setByPrefix(value, form, prefix, opt)
+	}
+
 	vs, ok := form[tagValue]
+	if !ok && isNestable(value.Type()) && !opt.binding.hasConverter(value.Type()) {
+		nested, err := nestedValues(form, tagValue)
+		if err != nil {
+			return false, err
+		}
+		if len(nested) > 0 {
+			isSet, err = setByNestedForm(value, field, nested, tagValue, opt)
+			if isSet && err == nil {
+				opt.record(tagValue, false)
+			}
+			return isSet, err
+		}
+	}
+	kind := formKind(value, opt)
+	if ok && opt.omitEmpty && kind != reflect.Slice && kind != reflect.Array && isEmptyValues(vs) {
+		vs, ok = nil, false // empty scalar values count as absent
+	}
 	if !ok && !opt.isDefaultExists {
 		return false, nil
 	}
 
-	switch value.Kind() {
+	if ok {
+		opt.use(tagValue)
+	}
+	opt.record(tagValue, !ok)
+
+	if o, isOptional := optionalOf(value); isOptional {
+		if ok && isEmptyValues(vs) {
+			o.setOptional(true)
+			return true, nil
+		}
+		isSet, err = setByForm(o.optionalElem(), field, form, tagValue, opt)
+		if isSet && err == nil {
+			o.setOptional(false)
+		}
+		return isSet, err
+	}
+
+	if u, isUnmarshaler := unmarshalerOf(value); isUnmarshaler {
+		if !ok {
+			vs = []string{opt.defaultValue}
+		}
+		return true, u.UnmarshalParams(vs)
+	}
+
+	switch kind {
 	case reflect.Slice:
 		if !ok {
 			vs = []string{opt.defaultValue}
 		}
-		return true, setSlice(vs, value, field)
+		return true, setSlice(vs, value, field, opt)
 	case reflect.Array:
 		if !ok {
 			vs = []string{opt.defaultValue}
@@ -176,7 +322,7 @@ func setByForm(value reflect.Value, field reflect.StructField, form map[string][
 		if len(vs) != value.Len() {
 			return false, fmt.Errorf("%q is not valid value for %s", vs, value.Type().String())
 		}
-		return true, setArray(vs, value, field)
+		return true, setArray(vs, value, field, opt)
 	default:
 		var val string
 		if !ok {
@@ -184,13 +330,39 @@ func setByForm(value reflect.Value, field reflect.StructField, form map[string][
 		}
 
 		if len(vs) > 0 {
-			val = vs[0]
+			if val, err = opt.duplicates.pick(opt.tag, opt.wireKey(tagValue), vs, DuplicateFirst); err != nil {
+				return false, err
+			}
 		}
-		return true, setWithProperType(val, value, field)
+		return true, setWithProperType(val, value, field, opt)
+	}
+}
+
+// formKind returns the kind setByForm treats value as. Text unmarshalers and
+// types with a converter take a single value even when they are slices or arrays.
+func formKind(value reflect.Value, opt setOptions) reflect.Kind {
+	if _, isTextUnmarshaler := textUnmarshalerOf(value); isTextUnmarshaler || opt.binding.hasConverter(value.Type()) {
+		return reflect.Invalid
 	}
+	return value.Kind()
+}
+
+// isEmptyValues reports whether vs holds no value or a single empty value.
+func isEmptyValues(vs []string) bool {
+	return len(vs) == 0 || len(vs) == 1 && vs[0] == ""
 }
 
-func setWithProperType(val string, value reflect.Value, field reflect.StructField) error {
+func setWithProperType(val string, value reflect.Value, field reflect.StructField, opt setOptions) error {
+	if ok, err := opt.binding.convert(val, value, field); ok {
+		return err
+	}
+	if u, ok := unmarshalerOf(value); ok {
+		return u.UnmarshalParams([]string{val})
+	}
+	if u, ok := textUnmarshalerOf(value); ok {
+		return u.UnmarshalText([]byte(val))
+	}
+
 	switch value.Kind() {
 	case reflect.Int:
 		return setIntField(val, 0, value)
@@ -229,15 +401,42 @@ func setWithProperType(val string, value reflect.Value, field reflect.StructFiel
 		case time.Time:
 			return setTimeField(val, field, value)
 		}
-		return json.Unmarshal(stringToBytes(val), value.Addr().Interface())
+		return opt.binding.jsonCodec().Unmarshal(stringToBytes(val), value.Addr().Interface())
 	case reflect.Map:
-		return json.Unmarshal(stringToBytes(val), value.Addr().Interface())
+		return opt.binding.jsonCodec().Unmarshal(stringToBytes(val), value.Addr().Interface())
+	case reflect.Ptr:
+		vPtr := value
+		if value.IsNil() {
+			vPtr = reflect.New(value.Type().Elem())
+		}
+		if err := setWithProperType(val, vPtr.Elem(), field, opt); err != nil {
+			return err
+		}
+		value.Set(vPtr)
 	default:
 		return errUnknownType
 	}
 	return nil
 }
 
+// unmarshalerOf returns the Unmarshaler implemented by a pointer to value.
+func unmarshalerOf(value reflect.Value) (Unmarshaler, bool) {
+	if !value.CanAddr() || !reflect.PtrTo(value.Type()).Implements(unmarshalerType) {
+		return nil, false
+	}
+	return value.Addr().Interface().(Unmarshaler), true
+}
+
+// textUnmarshalerOf returns the encoding.TextUnmarshaler implemented by a
+// pointer to value. time.Time is left to setTimeField, which honours the
+// time_format, time_utc and time_location tags.
+func textUnmarshalerOf(value reflect.Value) (encoding.TextUnmarshaler, bool) {
+	if !value.CanAddr() || value.Type() == timeType || !reflect.PtrTo(value.Type()).Implements(textUnmarshalerType) {
+		return nil, false
+	}
+	return value.Addr().Interface().(encoding.TextUnmarshaler), true
+}
+
 func setIntField(val string, bitSize int, field reflect.Value) error {
 	if val == "" {
 		val = "0"
@@ -332,9 +531,9 @@ func setTimeField(val string, structField reflect.StructField, value reflect.Val
 	return nil
 }
 
-func setArray(vals []string, value reflect.Value, field reflect.StructField) error {
+func setArray(vals []string, value reflect.Value, field reflect.StructField, opt setOptions) error {
 	for i, s := range vals {
-		err := setWithProperType(s, value.Index(i), field)
+		err := setWithProperType(s, value.Index(i), field, opt)
 		if err != nil {
 			return err
 		}
@@ -342,9 +541,9 @@ func setArray(vals []string, value reflect.Value, field reflect.StructField) err
 	return nil
 }
 
-func setSlice(vals []string, value reflect.Value, field reflect.StructField) error {
+func setSlice(vals []string, value reflect.Value, field reflect.StructField, opt setOptions) error {
 	slice := reflect.MakeSlice(value.Type(), len(vals), len(vals))
-	err := setArray(vals, slice, field)
+	err := setArray(vals, slice, field, opt)
 	if err != nil {
 		return err
 	}
@@ -369,7 +568,69 @@ func head(str, sep string) (head string, tail string) {
 	return str[:idx], str[idx+len(sep):]
 }
 
-func setFormMap(ptr interface{}, form map[string][]string) error {
+// setByPrefix captures the parameters whose key starts with prefix into the
+// map value, keyed by the rest of their key. Header names match the prefix
+// case-insensitively and keep their canonical form, header:"X-Meta-*"
+// captures x-meta-trace-id as "Trace-Id". Query, form and uri keys match
+// case-sensitively and keep the rest as sent, query:"filter_*" captures
+// filter_Name as "Name".
+func setByPrefix(value reflect.Value, form map[string][]string, prefix string, opt setOptions) (bool, error) {
+	captured := make(map[string][]string)
+	wireKeys := make(map[string]string)
+	for key, vs := range form {
+		if opt.tag == "header" {
+			key = textproto.CanonicalMIMEHeaderKey(key)
+		}
+		if len(key) <= len(prefix) ||
+			!(key[:len(prefix)] == prefix || opt.tag == "header" && strings.EqualFold(key[:len(prefix)], prefix)) {
+			continue
+		}
+		rest := key[len(prefix):]
+		captured[rest] = append(captured[rest], vs...)
+		wireKeys[rest] = opt.wireKey(key)
+	}
+	if len(captured) == 0 {
+		return false, nil
+	}
+	opt.record(prefix+"*", false)
+	opt.wireKeys = wireKeys
+	return true, setTypedMap(value, captured, opt)
+}
+
+// isMapTarget reports whether obj is a map with a string key, or a pointer
+// to one.
+func isMapTarget(obj interface{}) bool {
+	t := reflect.TypeOf(obj)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t != nil && t.Kind() == reflect.Map && t.Key().Kind() == reflect.String
+}
+
+// setTypedMap sets the entries of a map with a string key from form, each
+// value converted like a field of the element type.
+func setTypedMap(value reflect.Value, form map[string][]string, opt setOptions) error {
+	if value.IsNil() {
+		if !value.CanSet() {
+			return ErrConvertToMapString
+		}
+		value.Set(reflect.MakeMap(value.Type()))
+	}
+	for key, vs := range form {
+		elem := reflect.New(value.Type().Elem()).Elem()
+		elemOpt := opt.mapEntry()
+		elemOpt.path = opt.path + "[" + key + "]"
+		if _, err := setByForm(elem, emptyField, map[string][]string{key: vs}, key, elemOpt); err != nil {
+			return conversionError(opt.wireKey(key), vs, elem.Type(), err)
+		}
+		mapKey := reflect.New(value.Type().Key()).Elem()
+		mapKey.SetString(key)
+		value.SetMapIndex(mapKey, elem)
+	}
+	return nil
+}
+
+func setFormMap(ptr interface{}, form map[string][]string, tag string, policy DuplicatePolicy) error {
 	el := reflect.TypeOf(ptr).Elem()
 
 	if el.Kind() == reflect.Slice {
@@ -389,7 +650,11 @@ func setFormMap(ptr interface{}, form map[string][]string) error {
 		return ErrConvertToMapString
 	}
 	for k, v := range form {
-		ptrMap[k] = v[len(v)-1] // pick last
+		val, err := policy.pick(tag, k, v, DuplicateLast)
+		if err != nil {
+			return err
+		}
+		ptrMap[k] = val
 	}
 
 	return nil
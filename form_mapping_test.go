@@ -5,7 +5,13 @@
 package binding
 
 import (
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -52,7 +58,7 @@ func TestMappingBaseTypes(t *testing.T) {
 
 		field := val.Elem().Type().Field(0)
 
-		_, err := mapping(val, emptyField, formSource{field.Name: {tt.form}}, "form")
+		_, err := mapping(val, emptyField, formSource{field.Name: {tt.form}}, setOptions{tag: "form"})
 		assert.NoError(t, err, testName)
 
 		actual := val.Elem().Field(0).Interface()
@@ -301,3 +307,147 @@ func TestMappingIgnoredCircularRef(t *testing.T) {
 	err := mappingByPtr(&s, formSource{}, "form")
 	assert.NoError(t, err)
 }
+
+type testUserID int
+
+func (id *testUserID) UnmarshalText(text []byte) error {
+	n, err := strconv.Atoi(strings.TrimPrefix(string(text), "u-"))
+	*id = testUserID(n)
+	return err
+}
+
+type testUUID [4]byte
+
+func (u *testUUID) UnmarshalText(text []byte) error {
+	_, err := hex.Decode(u[:], text)
+	return err
+}
+
+type testCSV []string
+
+func (c *testCSV) UnmarshalParams(values []string) error {
+	for _, v := range values {
+		*c = append(*c, strings.Split(v, ",")...)
+	}
+	return nil
+}
+
+type testMoney struct {
+	Cents int64
+}
+
+func (m *testMoney) UnmarshalParams(values []string) error {
+	if len(values) != 1 {
+		return errors.New("money takes a single value")
+	}
+	f, err := strconv.ParseFloat(values[0], 64)
+	m.Cents = int64(f * 100)
+	return err
+}
+
+func TestMappingTextUnmarshaler(t *testing.T) {
+	var s struct {
+		ID    testUserID   `form:"id"`
+		IDs   []testUserID `form:"ids"`
+		UUID  testUUID     `form:"uuid"`
+		Ptr   *testUUID    `form:"ptr"`
+		IP    net.IP       `form:"ip"`
+		IPs   []net.IP     `form:"ips"`
+		Big   big.Int      `form:"big"`
+		Array [2]big.Int   `form:"array"`
+	}
+
+	err := mapForm(&s, map[string][]string{
+		"id":    {"u-7"},
+		"ids":   {"u-1", "u-2"},
+		"uuid":  {"0102abcd"},
+		"ptr":   {"ffffffff"},
+		"ip":    {"127.0.0.1"},
+		"ips":   {"10.0.0.1", "::1"},
+		"big":   {"123456789012345678901234567890"},
+		"array": {"1", "2"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, testUserID(7), s.ID)
+	assert.Equal(t, []testUserID{1, 2}, s.IDs)
+	assert.Equal(t, testUUID{0x01, 0x02, 0xab, 0xcd}, s.UUID)
+	assert.Equal(t, &testUUID{0xff, 0xff, 0xff, 0xff}, s.Ptr)
+	assert.Equal(t, "127.0.0.1", s.IP.String())
+	assert.Equal(t, "::1", s.IPs[1].String())
+	assert.Equal(t, "123456789012345678901234567890", s.Big.String())
+	assert.Equal(t, int64(2), s.Array[1].Int64())
+
+	err = mapForm(&s, map[string][]string{"uuid": {"zz"}})
+	assert.Error(t, err)
+}
+
+func TestMappingUnmarshaler(t *testing.T) {
+	var s struct {
+		Tags   testCSV     `form:"tags,default=a"`
+		Price  testMoney   `form:"price"`
+		Prices []testMoney `form:"prices"`
+	}
+
+	err := mapForm(&s, map[string][]string{
+		"price":  {"1.25"},
+		"prices": {"1", "2.5"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, testCSV{"a"}, s.Tags)
+	assert.Equal(t, int64(125), s.Price.Cents)
+	assert.Equal(t, []testMoney{{100}, {250}}, s.Prices)
+
+	s.Tags = nil
+	err = mapForm(&s, map[string][]string{"tags": {"x,y", "z"}})
+	assert.NoError(t, err)
+	assert.Equal(t, testCSV{"x", "y", "z"}, s.Tags)
+
+	err = mapForm(&s, map[string][]string{"price": {"1", "2"}})
+	assert.Error(t, err)
+}
+
+func TestMappingPtrElements(t *testing.T) {
+	var s struct {
+		Ints     []*int            `form:"ints"`
+		Times    []*time.Time      `form:"times" time_format:"2006-01-02" time_utc:"1"`
+		Strings  [3]*string        `form:"strings"`
+		PtrPtrs  []**int           `form:"ptr_ptrs"`
+		SlicePtr *[]string         `form:"slice_ptr"`
+		Map      map[string]*int   `form:"map"`
+		Absent   []*int            `form:"absent"`
+		Units    []*testUserID     `form:"units"`
+		Deep     *[]**big.Int      `form:"deep"`
+		Nested   map[string]**bool `form:"nested"`
+	}
+
+	err := mapForm(&s, map[string][]string{
+		"ints":        {"1", "", "3"},
+		"times":       {"2019-01-20"},
+		"strings":     {"a", "b", "c"},
+		"ptr_ptrs":    {"7"},
+		"slice_ptr":   {"x", "y"},
+		"map[one]":    {"1"},
+		"units":       {"u-5"},
+		"deep":        {"42"},
+		"nested[yes]": {"true"},
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, s.Ints, 3) {
+		assert.Equal(t, 1, *s.Ints[0])
+		assert.Equal(t, 0, *s.Ints[1])
+		assert.Equal(t, 3, *s.Ints[2])
+	}
+	assert.Equal(t, "2019-01-20 00:00:00 +0000 UTC", s.Times[0].String())
+	assert.Equal(t, "b", *s.Strings[1])
+	assert.Equal(t, 7, **s.PtrPtrs[0])
+	assert.Equal(t, &[]string{"x", "y"}, s.SlicePtr)
+	assert.Equal(t, 1, *s.Map["one"])
+	assert.Nil(t, s.Absent)
+	assert.Equal(t, testUserID(5), *s.Units[0])
+	assert.Equal(t, int64(42), (**(*s.Deep)[0]).Int64())
+	assert.True(t, **s.Nested["yes"])
+
+	err = mapForm(&s, map[string][]string{"ints": {"x"}})
+	assert.Error(t, err)
+}
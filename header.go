@@ -6,14 +6,21 @@ import (
 	"reflect"
 )
 
-type headerBinding struct{}
+type headerBinding struct {
+	binding *Binding
+	rec     *recorder
+}
 
-func (headerBinding) Bind(req *http.Request, obj interface{}) error {
-	return mapHeader(obj, req.Header)
+func (headerBinding) withBinding(b *Binding, rec *recorder) Binder {
+	return headerBinding{b, rec}
 }
 
-func mapHeader(ptr interface{}, h map[string][]string) error {
-	return mappingByPtr(ptr, headerSource(h), "header")
+func (hb headerBinding) Bind(req *http.Request, obj interface{}) error {
+	hb.rec.input("header", req.Header)
+	if isMapTarget(obj) {
+		return hb.binding.mapFormByTag(obj, req.Header, "header", hb.rec)
+	}
+	return hb.binding.mappingByPtr(obj, headerSource(req.Header), "header", hb.rec)
 }
 
 type headerSource map[string][]string
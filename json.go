@@ -3,13 +3,19 @@ package binding
 import (
 	"bytes"
 	"io"
+	"math"
 	"net/http"
+	"reflect"
 
 	jsoniter "github.com/json-iterator/go"
 )
 
 var json = jsoniter.ConfigCompatibleWithStandardLibrary
 
+var jsonUnmarshalerType = reflect.TypeOf((*interface {
+	UnmarshalJSON([]byte) error
+})(nil)).Elem()
+
 // EnableDecoderUseNumber is used to call the UseNumber method on the JSON
 // Decoder instance. UseNumber causes the Decoder to unmarshal a number into an
 // interface{} as a Number instead of as a float64.
@@ -21,21 +27,98 @@ var EnableDecoderUseNumber = false // TODO(m) migrate to binder global options
 // keys which do not match any non-ignored, exported fields in the destination.
 var EnableDecoderDisallowUnknownFields = false // TODO(m) migrate to binder global options
 
-type jsonBinder struct{}
+type jsonBinder struct {
+	binding *Binding
+	rec     *recorder
+	lenient bool
+}
 
-func (jsonBinder) Bind(req *http.Request, obj interface{}) error {
+func (jb jsonBinder) withBinding(b *Binding, rec *recorder) Binder {
+	return jsonBinder{b, rec, jb.lenient}
+}
+
+func (jb jsonBinder) Bind(req *http.Request, obj interface{}) error {
 	if req == nil || req.Body == nil || req.ContentLength == 0 {
 		return nil
 	}
-	return decodeJSON(req.Body, obj)
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	if err = jb.binding.jsonLimits().check(body, "", obj); err != nil {
+		return err
+	}
+	return jb.bindBody(body, "", body, obj)
+}
+
+func (jb jsonBinder) bindAt(req *http.Request, pointer string, obj interface{}) error {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return err
+	}
+	if req == nil || req.Body == nil || req.ContentLength == 0 {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	if err = jb.binding.jsonLimits().check(body, pointer, obj); err != nil {
+		return err
+	}
+	data, ok, err := jsonSubtree(body, tokens)
+	if err != nil {
+		return jsonError(err, body, "", body, obj)
+	}
+	if !ok {
+		return nil
+	}
+	return jb.bindBody(body, pointer, data, obj)
+}
+
+func (jb jsonBinder) BindBody(body []byte, obj interface{}) error {
+	if err := jb.binding.jsonLimits().check(body, "", obj); err != nil {
+		return err
+	}
+	return jb.bindBody(body, "", body, obj)
+}
+
+// bindBody decodes data, the value at pointer of body, into obj. Errors are
+// located in body.
+func (jb jsonBinder) bindBody(body []byte, pointer string, data []byte, obj interface{}) error {
+	lenient, timed := jb.isLenient(obj), obj != nil && hasTimeTags(reflect.TypeOf(obj))
+	var times []bodyTime
+	if lenient || timed {
+		doc, err := decodeJSONDoc(jb.binding.jsonCodec(), bytes.NewReader(data))
+		if err == nil && lenient {
+			doc, err = jb.coerceJSON(doc, obj, pointer)
+		}
+		if err == nil && timed {
+			doc, err = parseDocTimes(jsonFormat, doc, reflect.TypeOf(obj), nil, nil, pointer, &times)
+		}
+		if err != nil {
+			return jsonError(err, body, pointer, data, obj)
+		}
+		if data, err = jb.binding.jsonCodec().Marshal(doc); err != nil {
+			return err
+		}
+	}
+
+	if err := decodeJSON(jb.binding.jsonCodec(), bytes.NewReader(data), obj); err != nil {
+		return jsonError(err, body, pointer, data, obj)
+	}
+	setBodyTimes(jsonFormat, obj, times)
+	return jb.rec.recordJSON(jb.binding.jsonCodec(), data, obj, pointer)
 }
 
-func (jsonBinder) BindBody(body []byte, obj interface{}) error {
-	return decodeJSON(bytes.NewReader(body), obj)
+// isLenient reports whether some values of obj decode leniently.
+func (jb jsonBinder) isLenient(obj interface{}) bool {
+	return jb.lenient || obj != nil && isLenientJSONType(reflect.TypeOf(obj))
 }
 
-func decodeJSON(r io.Reader, obj interface{}) error {
-	decoder := json.NewDecoder(r)
+func decodeJSON(codec JSONCodec, r io.Reader, obj interface{}) error {
+	decoder := codec.NewDecoder(r)
 	if EnableDecoderUseNumber {
 		decoder.UseNumber()
 	}
@@ -44,3 +127,57 @@ func decodeJSON(r io.Reader, obj interface{}) error {
 	}
 	return decoder.Decode(obj)
 }
+
+// jsonSubtree returns the value of the JSON document data at the reference
+// tokens of a JSON Pointer, and false when there is none. The values around
+// it are skipped, not decoded.
+func jsonSubtree(data []byte, tokens []string) ([]byte, bool, error) {
+	iter := json.BorrowIterator(data)
+	defer json.ReturnIterator(iter)
+
+	for _, token := range tokens {
+		var found bool
+		switch iter.WhatIsNext() {
+		case jsoniter.ObjectValue:
+			iter.ReadMapCB(func(iter *jsoniter.Iterator, key string) bool {
+				if found = key == token; !found {
+					iter.Skip()
+				}
+				return !found
+			})
+		case jsoniter.ArrayValue:
+			index, err := jsonArrayIndex(token, math.MaxInt)
+			if err != nil {
+				return nil, false, nil
+			}
+			iter.ReadArrayCB(func(iter *jsoniter.Iterator) bool {
+				if found = index == 0; !found {
+					index--
+					iter.Skip()
+				}
+				return !found
+			})
+		default:
+			iter.Skip()
+		}
+		if iter.Error != nil && iter.Error != io.EOF {
+			return nil, false, iter.Error
+		}
+		if !found {
+			return nil, false, nil
+		}
+	}
+
+	data = iter.SkipAndReturnBytes()
+	if iter.Error != nil && iter.Error != io.EOF {
+		return nil, false, iter.Error
+	}
+	return data, true, nil
+}
+
+// isJSONLeaf reports whether values of t decode themselves from JSON.
+func isJSONLeaf(t reflect.Type) bool {
+	return t == timeType ||
+		reflect.PtrTo(t).Implements(jsonUnmarshalerType) ||
+		reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
@@ -0,0 +1,195 @@
+package binding
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrJSONDuplicateKey JSON object has the same key twice
+	ErrJSONDuplicateKey = errors.New("json: duplicate object key")
+
+	// ErrJSONTooDeep JSON document nests deeper than JSONLimits.MaxDepth
+	ErrJSONTooDeep = errors.New("json: document nested too deep")
+
+	// ErrJSONTooManyTokens JSON document has more than JSONLimits.MaxTokens tokens
+	ErrJSONTooManyTokens = errors.New("json: document has too many tokens")
+
+	// ErrJSONArrayTooLong JSON array has more than JSONLimits.MaxArrayLength elements
+	ErrJSONArrayTooLong = errors.New("json: array too long")
+
+	// ErrJSONStringTooLong JSON string or key is longer than JSONLimits.MaxStringLength
+	ErrJSONStringTooLong = errors.New("json: string too long")
+)
+
+// JSONLimits bounds the JSON bodies a Binding decodes. Bodies are checked
+// before anything is decoded into the target, a violation is a *BodyError
+// wrapping one of ErrJSONDuplicateKey, ErrJSONTooDeep, ErrJSONTooManyTokens,
+// ErrJSONArrayTooLong and ErrJSONStringTooLong. Zero fields impose no limit.
+type JSONLimits struct {
+	// RejectDuplicateKeys rejects objects which have the same key twice,
+	// which JSON decoders resolve by letting the last one win. Keys which
+	// decode into the same struct field, such as "role" and "Role", are
+	// duplicates too.
+	RejectDuplicateKeys bool
+
+	// MaxDepth is the maximum nesting of objects and arrays.
+	MaxDepth int
+
+	// MaxTokens is the maximum number of tokens: delimiters, keys and values.
+	MaxTokens int
+
+	// MaxArrayLength is the maximum number of elements of an array.
+	MaxArrayLength int
+
+	// MaxStringLength is the maximum length in bytes of a string or key.
+	MaxStringLength int
+}
+
+// SetJSONLimits sets the JSON limits of the package level Binding.
+func SetJSONLimits(limits JSONLimits) {
+	defaultBinding.SetJSONLimits(limits)
+}
+
+// SetJSONLimits sets the limits of the JSON bodies b decodes.
+func (b *Binding) SetJSONLimits(limits JSONLimits) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.limits = limits
+}
+
+func (b *Binding) jsonLimits() JSONLimits {
+	b = b.orDefault()
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.limits
+}
+
+// check returns the first violation of l by the JSON document data, decoded
+// into obj at pointer. Syntax errors are left to the decoder.
+func (l JSONLimits) check(data []byte, pointer string, obj interface{}) error {
+	if l == (JSONLimits{}) {
+		return nil
+	}
+
+	type container struct {
+		object bool
+		t      reflect.Type // the type the object decodes into, if known
+		keys   map[string]bool
+		length int
+	}
+	prefix, _ := parseJSONPointer(pointer)
+	objType := reflect.TypeOf(obj)
+	var stack []container
+	var tokens int
+	var violation error
+	var at jsonToken
+
+	walkJSON(data, func(tok jsonToken) bool {
+		at = tok
+		tokens++
+		if l.MaxTokens > 0 && tokens > l.MaxTokens {
+			violation = ErrJSONTooManyTokens
+			return false
+		}
+		if s, ok := tok.Token.(string); ok && l.MaxStringLength > 0 && len(s) > l.MaxStringLength {
+			violation = ErrJSONStringTooLong
+			return false
+		}
+		if tok.End {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+
+		if n := len(stack); n > 0 {
+			top := &stack[n-1]
+			switch {
+			case tok.Key:
+				if !l.RejectDuplicateKeys {
+					return true
+				}
+				key := "key " + tok.Token.(string)
+				if top.t != nil {
+					if sf, ok := jsonFormat.field(top.t, tok.Token.(string)); ok {
+						key = fmt.Sprint("field ", sf.Index)
+					}
+				}
+				if top.keys[key] {
+					violation = ErrJSONDuplicateKey
+					return false
+				}
+				top.keys[key] = true
+				return true
+			case !top.object:
+				if top.length++; l.MaxArrayLength > 0 && top.length > l.MaxArrayLength {
+					violation = ErrJSONArrayTooLong
+					return false
+				}
+			}
+		}
+
+		switch tok.Token {
+		case stdjson.Delim('{'):
+			var t reflect.Type
+			if l.RejectDuplicateKeys {
+				t = jsonStructAt(objType, prefix, tok.Path())
+			}
+			stack = append(stack, container{object: true, t: t, keys: make(map[string]bool)})
+		case stdjson.Delim('['):
+			stack = append(stack, container{})
+		default:
+			return true
+		}
+		if l.MaxDepth > 0 && len(stack) > l.MaxDepth {
+			violation = ErrJSONTooDeep
+			return false
+		}
+		return true
+	})
+	if violation == nil {
+		return nil
+	}
+	return newJSONBodyError(violation, data, at.Start, at.Path(), prefix, objType)
+}
+
+// jsonStructAt returns the struct type which the value at path of a JSON
+// document decodes into, when t decodes its value at the reference tokens
+// prefix. It returns nil when the value does not decode into a struct.
+func jsonStructAt(t reflect.Type, prefix []string, path []pathElem) reflect.Type {
+	if t == nil || len(path) < len(prefix) {
+		return nil
+	}
+	for i, token := range prefix {
+		if path[i].token() != token {
+			return nil
+		}
+	}
+	for _, p := range path[len(prefix):] {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		switch {
+		case t.Kind() == reflect.Struct && p.index < 0 && !jsonFormat.leaf(t):
+			sf, ok := jsonFormat.field(t, p.key)
+			if !ok {
+				return nil
+			}
+			t = sf.Type
+		case t.Kind() == reflect.Map:
+			t = t.Elem()
+		case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && p.index >= 0:
+			t = t.Elem()
+		default:
+			return nil
+		}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || jsonFormat.leaf(t) {
+		return nil
+	}
+	return t
+}
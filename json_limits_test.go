@@ -0,0 +1,80 @@
+package binding
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONLimits(t *testing.T) {
+	b := New()
+	b.SetJSONLimits(JSONLimits{
+		RejectDuplicateKeys: true,
+		MaxDepth:            3,
+		MaxTokens:           40,
+		MaxArrayLength:      3,
+		MaxStringLength:     8,
+	})
+
+	type user struct {
+		Role  string   `json:"role"`
+		Tags  []string `json:"tags"`
+		Extra struct {
+			Nested interface{} `json:"nested"`
+		} `json:"extra"`
+	}
+
+	for body, want := range map[string]error{
+		`{"role": "user", "role": "admin"}`:             ErrJSONDuplicateKey,
+		`{"role": "user", "Role": "admin"}`:             ErrJSONDuplicateKey,
+		`{"extra": {"nested": {"deep": [1]}}}`:          ErrJSONTooDeep,
+		`{"tags": ["a", "b", "c", "d"]}`:                ErrJSONArrayTooLong,
+		`{"role": "administrator"}`:                     ErrJSONStringTooLong,
+		`{"extra": {"nested": {` + manyKeys(20) + `}}}`: ErrJSONTooManyTokens,
+	} {
+		var u user
+		u.Role = "guest"
+		req := requestWithBody("POST", "/", body)
+		req.Header.Set("Content-Type", MIMEJSON)
+		err := b.Bind(req, &u)
+		assert.ErrorIs(t, err, want, body)
+		assert.Equal(t, "guest", u.Role, body)
+	}
+
+	var u user
+	req := requestWithBody("POST", "/", "{\n  \"role\": \"user\",\n  \"role\": \"admin\"\n}")
+	req.Header.Set("Content-Type", MIMEJSON)
+	err := b.Bind(req, &u)
+	var bodyErr *BodyError
+	assert.ErrorAs(t, err, &bodyErr)
+	assert.Equal(t, 3, bodyErr.Line)
+	assert.Equal(t, "$.role", bodyErr.Path)
+	assert.Equal(t, "Role", bodyErr.Field)
+
+	req = requestWithBody("POST", "/", `{"role": "user", "tags": ["a", "b", "c"], "extra": {"nested": {"a": 1}}}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.NoError(t, b.Bind(req, &u))
+	assert.Equal(t, "user", u.Role)
+
+	// map keys which differ in case are distinct
+	labels := map[string]string{}
+	req = requestWithBody("POST", "/", `{"role": "user", "Role": "admin"}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.NoError(t, b.Bind(req, &labels))
+	assert.Equal(t, map[string]string{"role": "user", "Role": "admin"}, labels)
+
+	req = requestWithBody("POST", "/", `{"role": "user", "role": "admin"}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.NoError(t, Bind(req, &u))
+	assert.Equal(t, "admin", u.Role)
+}
+
+func manyKeys(n int) string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = `"k` + strconv.Itoa(i) + `": 1`
+	}
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,34 @@
+package binding
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidJSONPointer JSON Pointer does not start with "/"
+var ErrInvalidJSONPointer = errors.New("invalid JSON Pointer")
+
+var (
+	jsonPointerEscaper   = strings.NewReplacer("~", "~0", "/", "~1")
+	jsonPointerUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+)
+
+// parseJSONPointer splits an RFC 6901 JSON Pointer into its unescaped
+// reference tokens, the empty pointer refers to the whole document.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if pointer[0] != '/' {
+		return nil, ErrInvalidJSONPointer
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = jsonPointerUnescaper.Replace(token)
+	}
+	return tokens, nil
+}
+
+func escapeJSONPointer(key string) string {
+	return jsonPointerEscaper.Replace(key)
+}
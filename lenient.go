@@ -0,0 +1,225 @@
+package binding
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Coercion describes a JSON value the lenient mode converted to the type of
+// its field.
+type Coercion struct {
+	// Path is the path of the field, such as "Items[0].Price".
+	Path string
+
+	// Key is the JSON Pointer of the value, such as "/items/0/price".
+	Key string
+
+	// From is the JSON type of the value: "string", "number", "boolean",
+	// "object", "array" or "null".
+	From string
+
+	// To is the Go type of the field.
+	To string
+}
+
+// coerceJSON returns doc, the JSON document data decoded into maps and
+// slices, the value at pointer of the body, with the values of the fields
+// JSON decodes leniently, every field for the LenientJSON binder, fields with
+// the lenient option of the json tag otherwise, coerced to the types of their
+// fields:
+//
+//   - strings, numbers and booleans set scalar fields like form values do,
+//     so "2" sets an int and "true" or 1 a bool
+//   - a single value sets a slice of one element
+//   - null sets a non-pointer field to its zero value
+//
+// The recorder reports each coercion.
+func (jb jsonBinder) coerceJSON(doc interface{}, obj interface{}, pointer string) (interface{}, error) {
+	c := jsonCoercer{binding: jb.binding.orDefault(), rec: jb.rec}
+	return c.coerce(doc, reflect.TypeOf(obj), emptyField, jb.lenient, "", pointer)
+}
+
+var lenientJSONTypes sync.Map // reflect.Type → bool
+
+// isLenientJSONType reports whether a value of t has fields with the lenient
+// option of the json tag.
+func isLenientJSONType(t reflect.Type) bool {
+	if lenient, ok := lenientJSONTypes.Load(t); ok {
+		return lenient.(bool)
+	}
+	lenient := hasLenientJSON(t, make(map[reflect.Type]bool))
+	lenientJSONTypes.Store(t, lenient)
+	return lenient
+}
+
+func hasLenientJSON(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] || isJSONLeaf(t) {
+		return false
+	}
+	seen[t] = true
+	for _, sf := range jsonFormat.fields(t) {
+		if isLenientJSON(sf) || hasLenientJSON(sf.Type, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLenientJSON(sf reflect.StructField) bool {
+	_, opts := head(sf.Tag.Get("json"), ",")
+	return strings.Contains(","+opts+",", ",lenient,")
+}
+
+type jsonCoercer struct {
+	binding *Binding
+	rec     *recorder
+}
+
+// coerce returns doc, a JSON document decoded into maps and slices, with the
+// values set leniently converted to the types of their fields.
+func (c jsonCoercer) coerce(doc interface{}, t reflect.Type, field reflect.StructField, lenient bool, path, pointer string) (interface{}, error) {
+	if t.Kind() == reflect.Ptr {
+		if doc == nil {
+			return nil, nil
+		}
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	if doc == nil {
+		if !lenient || t.Kind() == reflect.Interface {
+			return nil, nil
+		}
+		c.record(path, pointer, doc, t)
+		return reflect.Zero(t).Interface(), nil
+	}
+	if isJSONLeaf(t) || t == bytesType {
+		return doc, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return doc, nil
+		}
+		for key, v := range object {
+			sf, ok := jsonFormat.field(t, key)
+			if !ok {
+				continue
+			}
+			v, err := c.coerce(v, sf.Type, sf, lenient || isLenientJSON(sf), joinFieldPath(path, sf.Name), pointer+"/"+escapeJSONPointer(key))
+			if err != nil {
+				return nil, err
+			}
+			object[key] = v
+		}
+	case reflect.Map:
+		object, ok := doc.(map[string]interface{})
+		if !ok {
+			return doc, nil
+		}
+		for key, v := range object {
+			v, err := c.coerce(v, t.Elem(), field, lenient, path+"["+key+"]", pointer+"/"+escapeJSONPointer(key))
+			if err != nil {
+				return nil, err
+			}
+			object[key] = v
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := doc.([]interface{})
+		if !ok {
+			if !lenient {
+				return doc, nil
+			}
+			c.record(path, pointer, doc, t)
+			array = []interface{}{doc}
+		}
+		for i, v := range array {
+			v, err := c.coerce(v, t.Elem(), field, lenient, path+"["+strconv.Itoa(i)+"]", pointer+"/"+strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			array[i] = v
+		}
+		return array, nil
+	case reflect.Interface:
+	default:
+		if !lenient || jsonKindMatches(doc, t.Kind()) {
+			return doc, nil
+		}
+		var val string
+		switch v := doc.(type) {
+		case string:
+			val = v
+		case bool:
+			val = strconv.FormatBool(v)
+		case jsonNumber:
+			val = v.String()
+		default:
+			return doc, nil
+		}
+		value := reflect.New(t).Elem()
+		if err := setWithProperType(val, value, field, setOptions{binding: c.binding}); err != nil {
+			return nil, conversionError(pointer, []string{val}, t, err)
+		}
+		c.record(path, pointer, doc, t)
+		return value.Interface(), nil
+	}
+	return doc, nil
+}
+
+func (c jsonCoercer) record(path, pointer string, doc interface{}, t reflect.Type) {
+	c.rec.coerce(Coercion{Path: path, Key: pointer, From: jsonType(doc), To: t.String()})
+}
+
+// jsonKindMatches reports whether JSON decodes doc into a value of kind as is.
+func jsonKindMatches(doc interface{}, kind reflect.Kind) bool {
+	switch doc.(type) {
+	case string:
+		return kind == reflect.String
+	case bool:
+		return kind == reflect.Bool
+	case jsonNumber:
+		return kind >= reflect.Int && kind <= reflect.Float64
+	}
+	return false
+}
+
+// jsonType returns the JSON type name of doc.
+func jsonType(doc interface{}) string {
+	switch doc.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	}
+	return "number"
+}
+
+// coerce records a coercion of lenient JSON decoding.
+func (rec *recorder) coerce(c Coercion) {
+	if rec == nil {
+		return
+	}
+	c.Path = joinFieldPath(rec.prefix, c.Path)
+	rec.coercions[c.Path] = c
+}
+
+func sortCoercions(coercions []Coercion) {
+	sort.Slice(coercions, func(i, j int) bool {
+		return coercions[i].Path < coercions[j].Path
+	})
+}
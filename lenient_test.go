@@ -0,0 +1,84 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLenientJSON(t *testing.T) {
+	type item struct {
+		Price float64 `json:"price"`
+	}
+	var s struct {
+		Page    int               `json:"page"`
+		Enabled bool              `json:"enabled"`
+		Name    string            `json:"name"`
+		IDs     []int             `json:"ids"`
+		Count   *uint             `json:"count"`
+		Limit   int               `json:"limit"`
+		Items   []item            `json:"items"`
+		Labels  map[string]string `json:"labels"`
+	}
+	s.Limit = 10
+
+	req := requestWithBody("POST", "/", `{"page": "2", "enabled": "true", "name": 42, "ids": "5", "count": "3", "limit": null,
+		"items": [{"price": "9.5"}], "labels": {"level": 1}}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	rec := newRecorder()
+	err := New().use(LenientJSON, rec).Bind(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.Page)
+	assert.True(t, s.Enabled)
+	assert.Equal(t, "42", s.Name)
+	assert.Equal(t, []int{5}, s.IDs)
+	assert.Equal(t, uint(3), *s.Count)
+	assert.Equal(t, 0, s.Limit)
+	assert.Equal(t, []item{{9.5}}, s.Items)
+	assert.Equal(t, map[string]string{"level": "1"}, s.Labels)
+
+	result := rec.bindResult()
+	assert.Equal(t, []Coercion{
+		{Path: "Count", Key: "/count", From: "string", To: "uint"},
+		{Path: "Enabled", Key: "/enabled", From: "string", To: "bool"},
+		{Path: "IDs", Key: "/ids", From: "string", To: "[]int"},
+		{Path: "IDs[0]", Key: "/ids/0", From: "string", To: "int"},
+		{Path: "Items[0].Price", Key: "/items/0/price", From: "string", To: "float64"},
+		{Path: "Labels[level]", Key: "/labels/level", From: "number", To: "string"},
+		{Path: "Limit", Key: "/limit", From: "null", To: "int"},
+		{Path: "Name", Key: "/name", From: "number", To: "string"},
+		{Path: "Page", Key: "/page", From: "string", To: "int"},
+	}, result.Coercions)
+
+	req = requestWithBody("POST", "/", `{"page": "two"}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	err = New().BindWith(req, &s, LenientJSON)
+	var convErr *ConversionError
+	assert.ErrorAs(t, err, &convErr)
+	assert.Equal(t, "/page", convErr.Key)
+
+	req = requestWithBody("POST", "/", `{"page": "2"}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.Error(t, Bind(req, &s))
+}
+
+func TestLenientJSONField(t *testing.T) {
+	var s struct {
+		Page int   `json:"page,lenient"`
+		IDs  []int `json:"ids,lenient"`
+		Size int   `json:"size"`
+	}
+
+	req := requestWithBody("POST", "/", `{"page": "2", "ids": 5, "size": 3}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	result, err := BindWithResult(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.Page)
+	assert.Equal(t, []int{5}, s.IDs)
+	assert.Equal(t, 3, s.Size)
+	assert.Len(t, result.Coercions, 2)
+
+	req = requestWithBody("POST", "/", `{"size": "3"}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	assert.Error(t, Bind(req, &s))
+}
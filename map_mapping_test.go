@@ -0,0 +1,121 @@
+package binding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testWeekday string
+
+func TestMappingTypedMaps(t *testing.T) {
+	counts := map[string]int{}
+	req := requestWithBody("GET", "/?a=1&b=2", "")
+	assert.NoError(t, Query.Bind(req, &counts))
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, counts)
+
+	var days map[testWeekday][]time.Time
+	req = requestWithBody("GET", "/?mon=2019-01-20T16:02:58Z&mon=2019-01-21T16:02:58Z&tue=2019-01-22T00:00:00Z", "")
+	assert.NoError(t, Query.Bind(req, &days))
+	assert.Len(t, days["mon"], 2)
+	assert.Equal(t, 22, days["tue"][0].Day())
+
+	var ptrs map[string]*float64
+	req = requestWithBody("GET", "/?pi=3.14", "")
+	assert.NoError(t, Query.Bind(req, &ptrs))
+	assert.Equal(t, 3.14, *ptrs["pi"])
+
+	var limits map[string]uint
+	req = requestWithBody("GET", "/", "")
+	req.Header.Set("X-Limit", "10")
+	assert.NoError(t, Header.Bind(req, &limits))
+	assert.Equal(t, uint(10), limits["X-Limit"])
+
+	counts = map[string]int{}
+	req = requestWithBody("GET", "/?a=1&b=x", "")
+	err := Query.Bind(req, &counts)
+	var convErr *ConversionError
+	if assert.ErrorAs(t, err, &convErr) {
+		assert.Equal(t, "b", convErr.Key)
+		assert.Equal(t, []string{"x"}, convErr.Values)
+		assert.Equal(t, "int", convErr.Type.String())
+	}
+	assert.EqualError(t, err, `can not convert b ["x"] to int: strconv.ParseInt: parsing "x": invalid syntax`)
+}
+
+func TestMappingTypedMapFields(t *testing.T) {
+	type item struct {
+		Price float64 `form:"price"`
+	}
+	var s struct {
+		Counts map[string]int                `form:"counts"`
+		Days   map[testWeekday][]time.Time   `form:"days" time_format:"2006-01-02"`
+		Items  map[string]*item              `form:"items"`
+		Limits map[string]*int               `form:"limits"`
+		Names  map[testWeekday]testUserID    `form:"names"`
+		Nested map[string]map[string]float32 `form:"nested"`
+	}
+	err := mapForm(&s, map[string][]string{
+		"counts[a]":       {"1"},
+		"days[mon]":       {"2019-01-20", "2019-01-27"},
+		"items[x][price]": {"9.5"},
+		"limits[max]":     {"100"},
+		"names[sun]":      {"u-7"},
+		"nested[a][b]":    {"0.5"},
+		"unrelated[a]":    {"1"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 1}, s.Counts)
+	assert.Len(t, s.Days["mon"], 2)
+	assert.Equal(t, 27, s.Days["mon"][1].Day())
+	assert.Equal(t, &item{Price: 9.5}, s.Items["x"])
+	assert.Equal(t, 100, *s.Limits["max"])
+	assert.Equal(t, testUserID(7), s.Names["sun"])
+	assert.Equal(t, float32(0.5), s.Nested["a"]["b"])
+
+	err = mapForm(&s, map[string][]string{"counts[a]": {"one"}})
+	var convErr *ConversionError
+	if assert.ErrorAs(t, err, &convErr) {
+		assert.Equal(t, "counts[a]", convErr.Key)
+		assert.Equal(t, []string{"one"}, convErr.Values)
+	}
+}
+
+func TestMappingPrefixCapture(t *testing.T) {
+	var s struct {
+		Meta    map[string][]string `header:"X-Meta-*"`
+		Trace   map[string]string   `header:"x-trace-*"`
+		Filters map[string]string   `query:"filter_*"`
+		Ranges  map[string][]int    `query:"range_*"`
+		Page    int                 `query:"page"`
+	}
+
+	req := requestWithBody("GET", "/?filter_name=mike&filter_Role=admin&Filter_x=1&filter_=empty&range_age=18&range_age=30&page=2", "")
+	req.Header.Add("X-Meta-Color", "red")
+	req.Header.Add("X-Meta-Color", "blue")
+	req.Header["x-meta-size"] = []string{"L"} // not canonical
+	req.Header.Set("X-Trace-Id", "abc")
+	req.Header.Set("X-Other", "1")
+
+	result, err := BindWithResult(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"Color": {"red", "blue"}, "Size": {"L"}}, s.Meta)
+	assert.Equal(t, map[string]string{"Id": "abc"}, s.Trace)
+	assert.Equal(t, map[string]string{"name": "mike", "Role": "admin"}, s.Filters)
+	assert.Equal(t, map[string][]int{"age": {18, 30}}, s.Ranges)
+	assert.Equal(t, 2, s.Page)
+
+	assert.Equal(t, FieldSource{Source: "query", Key: "filter_name"}, result.Fields["Filters[name]"])
+	assert.Equal(t, FieldSource{Source: "header", Key: "X-Meta-*"}, result.Fields["Meta"])
+	assert.Contains(t, result.Ignored, Input{"query", "Filter_x"})
+	assert.Contains(t, result.Ignored, Input{"header", "X-Other"})
+	assert.NotContains(t, result.Ignored, Input{"header", "X-Meta-Color"})
+
+	req = requestWithBody("GET", "/?range_age=x", "")
+	err = Query.Bind(req, &s)
+	var convErr *ConversionError
+	if assert.ErrorAs(t, err, &convErr) {
+		assert.Equal(t, "range_age", convErr.Key)
+	}
+}
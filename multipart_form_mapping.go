@@ -8,10 +8,16 @@ import (
 	"errors"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"reflect"
 )
 
-type multipartRequest http.Request
+// multipartRequest is a request with a parsed multipart form, partHeaders
+// holds the headers of the value parts in the order of MultipartForm.Value.
+type multipartRequest struct {
+	*http.Request
+	partHeaders map[string][]textproto.MIMEHeader
+}
 
 var _ setter = (*multipartRequest)(nil)
 
@@ -26,46 +32,99 @@ var (
 // TrySet tries to set a value by the multipart request with the binding a form file
 func (r *multipartRequest) TrySet(value reflect.Value, field reflect.StructField, key string, opt setOptions) (bool, error) {
 	if files := r.MultipartForm.File[key]; len(files) != 0 {
-		return setByMultipartFormFile(value, field, files)
+		for _, fh := range files {
+			fh.Filename = sanitizeFilename(fh.Filename)
+		}
+		if err := opt.files.checkTarget(key, value.Type()); err != nil {
+			return false, err
+		}
+		if err := opt.files.check(key, files); err != nil {
+			return false, err
+		}
+		if binder := partBinder(files[0].Header, opt); binder != nil && !isFileTarget(value.Type()) {
+			data, err := readFile(files[0], opt.files)
+			if err != nil {
+				return false, err
+			}
+			return setByPart(value, binder, data, key, opt)
+		}
+		opt.use(key)
+		opt.record(key, false)
+		return setByMultipartFormFile(value, field, files, opt.files)
 	}
 
+	if headers := r.partHeaders[key]; len(headers) != 0 {
+		if binder := partBinder(headers[0], opt); binder != nil && isStructuredTarget(value.Type()) {
+			return setByPart(value, binder, []byte(r.MultipartForm.Value[key][0]), key, opt)
+		}
+	}
 	return setByForm(value, field, r.MultipartForm.Value, key, opt)
 }
 
-func setByMultipartFormFile(value reflect.Value, field reflect.StructField, files []*multipart.FileHeader) (isSet bool, err error) {
+func setByMultipartFormFile(value reflect.Value, field reflect.StructField, files []*multipart.FileHeader, opt *fileOptions) (isSet bool, err error) {
+	switch value.Type() {
+	case fileHeaderPType:
+		value.Set(reflect.ValueOf(files[0]))
+		return true, nil
+	case fileHeaderType:
+		value.Set(reflect.ValueOf(*files[0]))
+		return true, nil
+	case fileType, reflect.PtrTo(fileType):
+		file, err := newFile(files[0], opt)
+		if err != nil {
+			return false, err
+		}
+		if value.Kind() == reflect.Ptr {
+			value.Set(reflect.ValueOf(file))
+		} else {
+			value.Set(reflect.ValueOf(*file))
+		}
+		return true, nil
+	case readCloserType:
+		f, err := files[0].Open()
+		if err != nil {
+			return false, err
+		}
+		value.Set(reflect.ValueOf(f))
+		return true, nil
+	}
+
 	switch value.Kind() {
-	case reflect.Ptr:
-		switch value.Interface().(type) {
-		case *multipart.FileHeader:
-			value.Set(reflect.ValueOf(files[0]))
-			return true, nil
+	case reflect.String:
+		data, err := readFile(files[0], opt)
+		if err != nil {
+			return false, err
 		}
-	case reflect.Struct:
-		switch value.Interface().(type) {
-		case multipart.FileHeader:
-			value.Set(reflect.ValueOf(*files[0]))
+		value.SetString(string(data))
+		return true, nil
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			data, err := readFile(files[0], opt)
+			if err != nil {
+				return false, err
+			}
+			value.SetBytes(data)
 			return true, nil
 		}
-	case reflect.Slice:
 		slice := reflect.MakeSlice(value.Type(), len(files), len(files))
-		isSet, err = setArrayOfMultipartFormFiles(slice, field, files)
+		isSet, err = setArrayOfMultipartFormFiles(slice, field, files, opt)
 		if err != nil || !isSet {
 			return isSet, err
 		}
 		value.Set(slice)
 		return true, nil
 	case reflect.Array:
-		return setArrayOfMultipartFormFiles(value, field, files)
+		return setArrayOfMultipartFormFiles(value, field, files, opt)
 	}
 	return false, ErrMultiFileHeader
 }
 
-func setArrayOfMultipartFormFiles(value reflect.Value, field reflect.StructField, files []*multipart.FileHeader) (isSet bool, err error) {
+func setArrayOfMultipartFormFiles(value reflect.Value, field reflect.StructField, files []*multipart.FileHeader, opt *fileOptions) (isSet bool, err error) {
 	if value.Len() != len(files) {
 		return false, ErrMultiFileHeaderLenInvalid
 	}
 	for i := range files {
-		set, err := setByMultipartFormFile(value.Index(i), field, files[i:i+1])
+		set, err := setByMultipartFormFile(value.Index(i), field, files[i:i+1], opt)
 		if err != nil || !set {
 			return set, err
 		}
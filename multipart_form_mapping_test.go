@@ -6,6 +6,8 @@ package binding
 
 import (
 	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -94,6 +96,97 @@ func TestFormMultipartBindingBindError(t *testing.T) {
 	}
 }
 
+func TestFormMultipartBindingBindFileTypes(t *testing.T) {
+	var s struct {
+		Bytes      []byte          `form:"file"`
+		String     string          `form:"file"`
+		File       File            `form:"file"`
+		FilePtr    *File           `form:"file,hash=sha256"`
+		Files      []*File         `form:"files,hash=md5"`
+		Contents   []string        `form:"files"`
+		ReadCloser io.ReadCloser   `form:"file"`
+		Readers    []io.ReadCloser `form:"files"`
+	}
+	req := createRequestMultipartFiles(t,
+		testFile{"file", "../hello.txt", []byte("hello")},
+		testFile{"files", "a.txt", []byte("a")},
+		testFile{"files", "b.txt", []byte("b")},
+	)
+	err := FormMultipart.Bind(req, &s)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []byte("hello"), s.Bytes)
+	assert.Equal(t, "hello", s.String)
+
+	assert.Equal(t, "hello.txt", s.File.Name)
+	assert.Equal(t, int64(5), s.File.Size)
+	assert.Equal(t, "application/octet-stream", s.File.ContentType)
+	assert.Equal(t, "text/plain; charset=utf-8", s.File.DetectedContentType)
+	assert.Equal(t, `form-data; name="file"; filename="../hello.txt"`, s.File.Header.Get("Content-Disposition"))
+	assert.Nil(t, s.File.Hash)
+	f, err := s.File.Open()
+	assert.NoError(t, err)
+	data, err := io.ReadAll(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.NoError(t, f.Close())
+
+	// the digest is computed as the file is read
+	assert.Nil(t, s.FilePtr.Hash)
+	f, err = s.FilePtr.Open()
+	assert.NoError(t, err)
+	_, err = f.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+	data, err = io.ReadAll(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.NoError(t, f.Close())
+	sum := sha256.Sum256([]byte("hello"))
+	assert.Equal(t, sum[:], s.FilePtr.Hash)
+	if assert.Len(t, s.Files, 2) {
+		f, err = s.Files[1].Open()
+		assert.NoError(t, err)
+		_, err = io.Copy(io.Discard, f)
+		assert.NoError(t, err)
+		sum := md5.Sum([]byte("b"))
+		assert.Equal(t, "b.txt", s.Files[1].Name)
+		assert.Equal(t, sum[:], s.Files[1].Hash)
+		assert.Nil(t, s.Files[0].Hash)
+	}
+	assert.Equal(t, []string{"a", "b"}, s.Contents)
+
+	data, err = io.ReadAll(s.ReadCloser)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.NoError(t, s.ReadCloser.Close())
+	assert.Len(t, s.Readers, 2)
+
+	var invalid struct {
+		File File `form:"file,hash=crc32"`
+	}
+	req = createRequestMultipartFiles(t, testFile{"file", "a.txt", []byte("a")})
+	assert.ErrorIs(t, FormMultipart.Bind(req, &invalid), ErrInvalidFileConstraint)
+
+	// the hash option needs a File to hold the digest
+	for _, target := range []interface{}{
+		&struct {
+			Bytes []byte `form:"file,hash=sha256"`
+		}{},
+		&struct {
+			String string `form:"file,hash=sha256"`
+		}{},
+		&struct {
+			ReadCloser io.ReadCloser `form:"file,hash=sha256"`
+		}{},
+		&struct {
+			Header *multipart.FileHeader `form:"file,hash=sha256"`
+		}{},
+	} {
+		req = createRequestMultipartFiles(t, testFile{"file", "a.txt", []byte("a")})
+		assert.ErrorIs(t, FormMultipart.Bind(req, target), ErrInvalidFileConstraint)
+	}
+}
+
 type testFile struct {
 	Fieldname string
 	Filename  string
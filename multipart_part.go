@@ -0,0 +1,188 @@
+package binding
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// bodyBinder is implemented by the binders which decode a body held in memory.
+type bodyBinder interface {
+	BindBody(body []byte, obj interface{}) error
+}
+
+// parseMultipartForm is http.Request.ParseMultipartForm which also returns
+// the headers of the value parts for targets with structured fields, the
+// standard library only keeps those of file parts. A second multipart.Reader
+// reads the headers from a copy of the body as it is parsed, nothing more is
+// buffered. When the form is already parsed, such as for the second body:""
+// target of a request, the headers are read from what is left of the body,
+// a form the caller parsed and consumed the body of has none.
+func parseMultipartForm(req *http.Request, maxMemory int64, obj interface{}) (map[string][]textproto.MIMEHeader, error) {
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || params["boundary"] == "" || !hasStructuredFields(reflect.TypeOf(obj)) {
+		return nil, req.ParseMultipartForm(maxMemory)
+	}
+	if req.MultipartForm != nil {
+		if req.Body == nil {
+			return nil, nil
+		}
+		return readPartHeaders(req.Body, params["boundary"]), nil
+	}
+
+	var headers map[string][]textproto.MIMEHeader
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer io.Copy(io.Discard, pr)
+		headers = readPartHeaders(pr, params["boundary"])
+	}()
+
+	body := req.Body
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(body, pw), body}
+	err = req.ParseMultipartForm(maxMemory)
+	req.Body = body
+	pw.Close()
+	<-done
+	return headers, err
+}
+
+// readPartHeaders reads the headers of the value parts of the multipart body
+// r, skipping their content.
+func readPartHeaders(r io.Reader, boundary string) map[string][]textproto.MIMEHeader {
+	headers := make(map[string][]textproto.MIMEHeader)
+	reader := multipart.NewReader(r, boundary)
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			return headers
+		}
+		if name := part.FormName(); name != "" && part.FileName() == "" {
+			headers[name] = append(headers[name], part.Header)
+		}
+	}
+}
+
+var structuredFieldTypes sync.Map // reflect.Type → bool
+
+// hasStructuredFields reports whether t, a pointer to a struct, has fields a
+// value part can set by its content type, which need the part headers.
+func hasStructuredFields(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if has, ok := structuredFieldTypes.Load(t); ok {
+		return has.(bool)
+	}
+	has := hasStructuredFieldsOf(t)
+	structuredFieldTypes.Store(t, has)
+	return has
+}
+
+func hasStructuredFieldsOf(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Tag.Get("form") == "-" {
+			continue
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && ft.Kind() == reflect.Struct {
+			if hasStructuredFieldsOf(ft) {
+				return true
+			}
+			continue
+		}
+		if sf.PkgPath == "" && isStructuredTarget(ft) {
+			return true
+		}
+	}
+	return false
+}
+
+// partBinder returns the body binder registered for the content type of a
+// part, structured syntax suffixes such as "+json" fall back to their format.
+// The binder has the converters and options of the Binding of opt, and
+// records the fields of the part under the path of opt.
+func partBinder(header textproto.MIMEHeader, opt setOptions) bodyBinder {
+	contentType := strings.ToLower(filterFlags(header.Get("Content-Type")))
+	if contentType == "" {
+		return nil
+	}
+	binder, ok := binders[contentType]
+	if !ok {
+		switch {
+		case strings.HasSuffix(contentType, "+json"):
+			binder = JSON
+		case strings.HasSuffix(contentType, "+xml"):
+			binder = XML
+		case strings.HasSuffix(contentType, "+yaml"):
+			binder = YAML
+		}
+	}
+	if binder == nil {
+		return nil
+	}
+	bb, _ := opt.binding.orDefault().use(binder, opt.rec.at(opt.path)).(bodyBinder)
+	return bb
+}
+
+// isStructuredTarget reports whether a part decoded by its content type can
+// set a value of t, plain strings, times and files keep the form semantics.
+func isStructuredTarget(t reflect.Type) bool {
+	if isFileTarget(t) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return t != timeType
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Interface:
+		return true
+	}
+	return false
+}
+
+// isFileTarget reports whether a file part sets a value of t as a file.
+func isFileTarget(t reflect.Type) bool {
+	switch t {
+	case fileType, reflect.PtrTo(fileType), fileHeaderType, fileHeaderPType, readCloserType, bytesType:
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Slice, reflect.Array:
+		return t.Elem().Kind() == reflect.Uint8 || isFileTarget(t.Elem())
+	}
+	return false
+}
+
+// setByPart decodes the content of the part key into value with binder.
+func setByPart(value reflect.Value, binder bodyBinder, data []byte, key string, opt setOptions) (bool, error) {
+	if !value.CanAddr() {
+		return false, nil
+	}
+	if err := binder.BindBody(data, value.Addr().Interface()); err != nil {
+		return false, err
+	}
+	opt.use(key)
+	opt.record(key, false)
+	return true, nil
+}
@@ -0,0 +1,201 @@
+package binding
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testPart struct {
+	Name        string
+	Filename    string
+	ContentType string
+	Header      textproto.MIMEHeader // further headers of the part
+	Content     string
+}
+
+// createRequestTypedParts builds a request of contentType, which may carry
+// parameters after the boundary, with a multipart body of parts. Parts
+// without a Name have no Content-Disposition.
+func createRequestTypedParts(t *testing.T, contentType string, parts ...testPart) *http.Request {
+	var body bytes.Buffer
+
+	mw := multipart.NewWriter(&body)
+	for _, part := range parts {
+		h := make(textproto.MIMEHeader)
+		if part.Name != "" {
+			disposition := `form-data; name="` + part.Name + `"`
+			if part.Filename != "" {
+				disposition += `; filename="` + part.Filename + `"`
+			}
+			h.Set("Content-Disposition", disposition)
+		}
+		if part.ContentType != "" {
+			h.Set("Content-Type", part.ContentType)
+		}
+		for k, v := range part.Header {
+			h[k] = v
+		}
+		w, err := mw.CreatePart(h)
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(part.Content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, mw.Close())
+
+	mediaType, params := contentType, ""
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		mediaType, params = contentType[:i], contentType[i:]
+	}
+	req, err := http.NewRequest("POST", "/", &body)
+	assert.NoError(t, err)
+	req.Header.Set("Content-Type", mediaType+"; boundary="+mw.Boundary()+params)
+	return req
+}
+
+type partMeta struct {
+	Title string   `json:"title" xml:"title" yaml:"title" toml:"title"`
+	Tags  []string `json:"tags" xml:"tag" yaml:"tags" toml:"tags"`
+}
+
+func TestFormMultipartStructuredParts(t *testing.T) {
+	var s struct {
+		JSON     partMeta          `form:"json"`
+		XML      *partMeta         `form:"xml"`
+		YAML     partMeta          `form:"yaml"`
+		TOML     partMeta          `form:"toml"`
+		Vendor   partMeta          `form:"vendor"`
+		FromFile partMeta          `form:"file"`
+		Labels   map[string]string `form:"labels"`
+		IDs      []int             `form:"ids"`
+		Raw      string            `form:"json"`
+		File     *File             `form:"file"`
+		Name     string            `form:"name"`
+	}
+
+	req := createRequestTypedParts(t, MIMEMultipartPOSTForm,
+		testPart{Name: "json", ContentType: "application/json; charset=utf-8", Content: `{"title":"json","tags":["a"]}`},
+		testPart{Name: "xml", ContentType: MIMEXML, Content: `<partMeta><title>xml</title><tag>b</tag></partMeta>`},
+		testPart{Name: "yaml", ContentType: MIMEYAML, Content: "title: yaml\ntags: [c]\n"},
+		testPart{Name: "toml", ContentType: MIMETOML, Content: "title = \"toml\"\ntags = [\"d\"]\n"},
+		testPart{Name: "vendor", ContentType: "application/vnd.api+json", Content: `{"title":"vendor"}`},
+		testPart{Name: "file", Filename: "meta.json", ContentType: MIMEJSON, Content: `{"title":"file"}`},
+		testPart{Name: "labels", ContentType: MIMEJSON, Content: `{"team":"core"}`},
+		testPart{Name: "ids", ContentType: MIMEJSON, Content: `[1,2,3]`},
+		testPart{Name: "name", Content: "plain"},
+	)
+	err := FormMultipart.Bind(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, partMeta{"json", []string{"a"}}, s.JSON)
+	assert.Equal(t, &partMeta{"xml", []string{"b"}}, s.XML)
+	assert.Equal(t, partMeta{"yaml", []string{"c"}}, s.YAML)
+	assert.Equal(t, partMeta{"toml", []string{"d"}}, s.TOML)
+	assert.Equal(t, partMeta{Title: "vendor"}, s.Vendor)
+	assert.Equal(t, partMeta{Title: "file"}, s.FromFile)
+	assert.Equal(t, map[string]string{"team": "core"}, s.Labels)
+	assert.Equal(t, []int{1, 2, 3}, s.IDs)
+	assert.Equal(t, `{"title":"json","tags":["a"]}`, s.Raw)
+	assert.Equal(t, "meta.json", s.File.Name)
+	assert.Equal(t, "plain", s.Name)
+
+	req = createRequestTypedParts(t, MIMEMultipartPOSTForm, testPart{Name: "json", ContentType: MIMEJSON, Content: `{"title":1}`})
+	err = FormMultipart.Bind(req, &s)
+	assert.Error(t, err)
+}
+
+func TestFormMultipartStructuredFileConstraints(t *testing.T) {
+	var s struct {
+		Meta partMeta `form:"meta,max_size=100B,accept=application/json|text/plain"`
+	}
+
+	large := `{"title":"` + strings.Repeat("x", 2000) + `"}`
+	req := createRequestTypedParts(t, MIMEMultipartPOSTForm,
+		testPart{Name: "meta", Filename: "../meta.json", ContentType: MIMEJSON, Content: large},
+	)
+	err := FormMultipart.Bind(req, &s)
+	var fileErr *FileError
+	if assert.ErrorAs(t, err, &fileErr) {
+		assert.ErrorIs(t, err, ErrFileTooLarge)
+		assert.Equal(t, "meta", fileErr.Field)
+		assert.Equal(t, "meta.json", fileErr.Filename)
+	}
+	assert.Empty(t, s.Meta.Title)
+
+	req = createRequestTypedParts(t, MIMEMultipartPOSTForm,
+		testPart{Name: "meta", Filename: "meta.json", ContentType: "application/vnd.api+json", Content: `{"title":"meta"}`},
+	)
+	assert.ErrorIs(t, FormMultipart.Bind(req, &s), ErrFileType)
+
+	req = createRequestTypedParts(t, MIMEMultipartPOSTForm,
+		testPart{Name: "meta", Filename: "meta.json", ContentType: MIMEJSON, Content: `{"title":"meta"}`},
+	)
+	assert.NoError(t, FormMultipart.Bind(req, &s))
+	assert.Equal(t, "meta", s.Meta.Title)
+}
+
+func TestFormMultipartStructuredPartsBinding(t *testing.T) {
+	type form struct {
+		Meta partMeta `form:"meta"`
+	}
+
+	b := New()
+	b.SetJSONLimits(JSONLimits{RejectDuplicateKeys: true})
+	var s form
+	req := createRequestTypedParts(t, MIMEMultipartPOSTForm,
+		testPart{Name: "meta", ContentType: MIMEJSON, Content: `{"title":"a","Title":"b"}`},
+	)
+	assert.ErrorIs(t, b.Bind(req, &s), ErrJSONDuplicateKey)
+
+	b = New()
+	b.SetStrict("json")
+	req = createRequestTypedParts(t, MIMEMultipartPOSTForm,
+		testPart{Name: "meta", ContentType: MIMEJSON, Content: `{"title":"a","extra":1}`},
+	)
+	assert.EqualError(t, b.Bind(req, &s), `unknown parameters: json "/extra"`)
+
+	req = createRequestTypedParts(t, MIMEMultipartPOSTForm,
+		testPart{Name: "meta", ContentType: MIMEYAML, Content: "title: yaml\n"},
+	)
+	result, err := BindWithResult(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, FieldSource{Source: "form", Key: "meta"}, result.Fields["Meta"])
+	assert.Equal(t, FieldSource{Source: "yaml", Key: "/title"}, result.Fields["Meta.Title"])
+}
+
+func TestFormMultipartPartHeaders(t *testing.T) {
+	assert.True(t, hasStructuredFields(reflect.TypeOf(&struct {
+		Meta partMeta `form:"meta"`
+	}{})))
+	assert.True(t, hasStructuredFields(reflect.TypeOf(&struct {
+		IDs []int `form:"ids"`
+	}{})))
+	assert.False(t, hasStructuredFields(reflect.TypeOf(&struct {
+		Name  string    `form:"name"`
+		Since time.Time `form:"since"`
+		File  *File     `form:"file"`
+		Meta  partMeta  `form:"-"`
+	}{})))
+
+	// the headers of the second body:"" target come from the re-read body
+	var s struct {
+		First struct {
+			Meta partMeta `form:"meta"`
+		} `body:""`
+		Second struct {
+			Meta partMeta `form:"meta"`
+		} `body:""`
+	}
+	req := createRequestTypedParts(t, MIMEMultipartPOSTForm,
+		testPart{Name: "meta", ContentType: MIMEYAML, Content: "title: yaml\n"},
+	)
+	assert.NoError(t, Bind(req, &s))
+	assert.Equal(t, "yaml", s.First.Meta.Title)
+	assert.Equal(t, "yaml", s.Second.Meta.Title)
+}
@@ -0,0 +1,212 @@
+package binding
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMultipartBodyTooLarge parts of a multipart/mixed or multipart/related body exceed defaultMemory
+	ErrMultipartBodyTooLarge = errors.New("multipart body too large")
+
+	// ErrMultipartRest field with the part:"*" tag is not a slice
+	ErrMultipartRest = errors.New(`part:"*" field must be a slice`)
+)
+
+// multipartPartsBinder binds multipart/mixed and multipart/related bodies.
+// Parts map onto fields by the part tag:
+//
+//	part:"name"          the part whose Content-Disposition name is name,
+//	                     the field name when the tag is empty
+//	part:"index=0"       the part at a position, counting from 0
+//	part:"cid=meta@host" the part whose Content-ID is meta@host
+//	part:"start"         the root part of multipart/related, the one named by
+//	                     the start parameter, or the first part
+//	part:"X-Kind=meta"   the first part whose X-Kind header is meta
+//	part:"*"             a slice receiving every part no other field took
+//
+// Each part is decoded by the body binder of its Content-Type into struct,
+// map and slice fields, []byte and string fields receive the raw content,
+// other fields are set like form values.
+type multipartPartsBinder struct {
+	binding *Binding
+	rec     *recorder
+}
+
+func (multipartPartsBinder) withBinding(b *Binding, rec *recorder) Binder {
+	return multipartPartsBinder{b, rec}
+}
+
+func (pb multipartPartsBinder) Bind(req *http.Request, obj interface{}) error {
+	_, params, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
+	if params["boundary"] == "" {
+		return http.ErrMissingBoundary
+	}
+
+	parts, err := readParts(multipart.NewReader(req.Body, params["boundary"]), defaultMemory)
+	if err != nil {
+		return err
+	}
+	source := &partsSource{parts: parts, used: make([]bool, len(parts)), start: params["start"]}
+	if err := pb.binding.mappingByPtr(obj, source, "part", pb.rec); err != nil {
+		return err
+	}
+	return source.setRest()
+}
+
+type rawPart struct {
+	header textproto.MIMEHeader
+	data   []byte
+}
+
+// readParts reads every part of r, at most maxMemory bytes of content.
+func readParts(r *multipart.Reader, maxMemory int64) ([]rawPart, error) {
+	var parts []rawPart
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(io.LimitReader(part, maxMemory+1))
+		if err != nil {
+			return nil, err
+		}
+		if maxMemory -= int64(len(data)); maxMemory < 0 {
+			return nil, ErrMultipartBodyTooLarge
+		}
+		parts = append(parts, rawPart{part.Header, data})
+	}
+}
+
+// partsSource is the setter of multipartPartsBinder.
+type partsSource struct {
+	parts []rawPart
+	used  []bool
+	start string
+	rest  []restField
+}
+
+var _ setter = (*partsSource)(nil)
+
+// restField is a field with the part:"*" tag, set once every other field
+// took its part.
+type restField struct {
+	value reflect.Value
+	field reflect.StructField
+	opt   setOptions
+}
+
+func (s *partsSource) TrySet(value reflect.Value, field reflect.StructField, key string, opt setOptions) (bool, error) {
+	if key == "*" {
+		s.rest = append(s.rest, restField{value, field, opt})
+		return true, nil
+	}
+
+	i := s.find(key)
+	if i < 0 {
+		return false, nil
+	}
+	s.used[i] = true
+	if err := setByRawPart(value, field, s.parts[i], opt); err != nil {
+		return false, err
+	}
+	opt.use(key)
+	opt.record(key, false)
+	return true, nil
+}
+
+// find returns the index of the part key selects, -1 when there is none.
+func (s *partsSource) find(key string) int {
+	if key == "start" {
+		start := strings.Trim(s.start, "<>")
+		for i, p := range s.parts {
+			if start == "" || strings.Trim(p.header.Get("Content-ID"), "<>") == start {
+				return i
+			}
+		}
+		return -1
+	}
+
+	if !strings.Contains(key, "=") {
+		for i, p := range s.parts {
+			if _, params, err := mime.ParseMediaType(p.header.Get("Content-Disposition")); err == nil && params["name"] == key {
+				return i
+			}
+		}
+		return -1
+	}
+
+	name, value := head(key, "=")
+	switch name {
+	case "index":
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 0 || i >= len(s.parts) {
+			return -1
+		}
+		return i
+	case "cid":
+		name, value = "Content-ID", strings.Trim(value, "<>")
+	}
+	for i, p := range s.parts {
+		if strings.Trim(p.header.Get(name), "<>") == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// setRest sets the part:"*" fields, slices of the parts no field took.
+func (s *partsSource) setRest() error {
+	for _, rest := range s.rest {
+		if rest.value.Kind() != reflect.Slice {
+			return ErrMultipartRest
+		}
+		slice := reflect.MakeSlice(rest.value.Type(), 0, len(s.parts))
+		for i, p := range s.parts {
+			if s.used[i] {
+				continue
+			}
+			elem := reflect.New(rest.value.Type().Elem()).Elem()
+			opt := rest.opt
+			opt.path += "[" + strconv.Itoa(slice.Len()) + "]"
+			if err := setByRawPart(elem, rest.field, p, opt); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		rest.value.Set(slice)
+		rest.opt.record("*", false)
+	}
+	return nil
+}
+
+// setByRawPart decodes the part into value by its Content-Type.
+func setByRawPart(value reflect.Value, field reflect.StructField, p rawPart, opt setOptions) error {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		return setByRawPart(value.Elem(), field, p, opt)
+	}
+	if binder := partBinder(p.header, opt); binder != nil && isStructuredTarget(value.Type()) {
+		return binder.BindBody(p.data, value.Addr().Interface())
+	}
+	if value.Type() == bytesType {
+		value.SetBytes(p.data)
+		return nil
+	}
+	return setWithProperType(string(p.data), value, field, opt)
+}
@@ -0,0 +1,87 @@
+package binding
+
+import (
+	"net/http"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createRequestRelated builds a request of contentType with parts given as
+// headers, but for "body" which is the content of the part.
+func createRequestRelated(t *testing.T, contentType string, params string, parts ...map[string]string) *http.Request {
+	typed := make([]testPart, len(parts))
+	for i, part := range parts {
+		h := make(textproto.MIMEHeader)
+		for k, v := range part {
+			if k != "body" {
+				h.Set(k, v)
+			}
+		}
+		typed[i] = testPart{Header: h, Content: part["body"]}
+	}
+	return createRequestTypedParts(t, contentType+params, typed...)
+}
+
+func TestBindingMultipartRelated(t *testing.T) {
+	var s struct {
+		Root    partMeta  `part:"start"`
+		Media   []byte    `part:"cid=media@example.com"`
+		Second  *partMeta `part:"index=1"`
+		Kind    string    `part:"X-Kind=note"`
+		Count   int       `part:"X-Kind=count"`
+		Missing string    `part:"cid=missing"`
+	}
+
+	req := createRequestRelated(t, MIMEMultipartRelated, `; type="application/json"; start="<root@example.com>"`,
+		map[string]string{"Content-ID": "<media@example.com>", "Content-Type": "image/png", "body": "\x89PNG"},
+		map[string]string{"Content-ID": "<root@example.com>", "Content-Type": MIMEJSON, "body": `{"title":"root"}`},
+		map[string]string{"X-Kind": "note", "body": "remember"},
+		map[string]string{"X-Kind": "count", "body": "42"},
+	)
+	err := Bind(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, partMeta{Title: "root"}, s.Root)
+	assert.Equal(t, []byte("\x89PNG"), s.Media)
+	assert.Equal(t, &partMeta{Title: "root"}, s.Second)
+	assert.Equal(t, "remember", s.Kind)
+	assert.Equal(t, 42, s.Count)
+	assert.Empty(t, s.Missing)
+
+	req = createRequestRelated(t, MIMEMultipartRelated, "",
+		map[string]string{"X-Kind": "count", "body": "many"},
+	)
+	assert.Error(t, Bind(req, &s))
+}
+
+func TestBindingMultipartMixed(t *testing.T) {
+	type item struct {
+		ID int `json:"id" xml:"id"`
+	}
+	var s struct {
+		Meta  partMeta `part:"meta"`
+		Items []item   `part:"*"`
+	}
+
+	req := createRequestRelated(t, MIMEMultipartMixed, "",
+		map[string]string{"Content-Type": MIMEJSON, "body": `{"id":1}`},
+		map[string]string{"Content-Disposition": `form-data; name="meta"`, "Content-Type": MIMEYAML, "body": "title: batch"},
+		map[string]string{"Content-Type": MIMEXML, "body": `<item><id>2</id></item>`},
+		map[string]string{"Content-Type": "application/vnd.item+json", "body": `{"id":3}`},
+	)
+	err := Bind(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, partMeta{Title: "batch"}, s.Meta)
+	assert.Equal(t, []item{{1}, {2}, {3}}, s.Items)
+
+	var invalid struct {
+		Rest string `part:"*"`
+	}
+	req = createRequestRelated(t, MIMEMultipartMixed, "", map[string]string{"body": "x"})
+	assert.ErrorIs(t, Bind(req, &invalid), ErrMultipartRest)
+
+	req = requestWithBody("POST", "/", "")
+	req.Header.Set("Content-Type", MIMEMultipartMixed)
+	assert.ErrorIs(t, Bind(req, &s), http.ErrMissingBoundary)
+}
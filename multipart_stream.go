@@ -0,0 +1,256 @@
+package binding
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrMultipartValueTooLarge text fields of a streamed multipart form exceed defaultMemory
+var ErrMultipartValueTooLarge = errors.New("multipart text fields too large")
+
+// PartHandler receives a file part of a multipart form streamed by
+// FormMultipartStream. The part can only be read until the handler returns.
+//
+//	type Upload struct {
+//		Title string              `form:"title"`
+//		Video binding.PartHandler `form:"video"`
+//	}
+type PartHandler func(part *multipart.Part) error
+
+var (
+	partHandlerType = reflect.TypeOf(PartHandler(nil))
+	readerType      = reflect.TypeOf((*io.Reader)(nil)).Elem()
+)
+
+// formMultipartStreamBinder binds a multipart form while reading it with a
+// multipart.Reader, nothing is buffered to memory or temporary files but the
+// text fields. Text fields are bound as they arrive, file parts are handed to
+// the PartHandler field of their name, parts without one are discarded.
+//
+// An io.Reader field receives its part and ends the binding, the caller then
+// reads the file from the request body, parts after it are not bound.
+type formMultipartStreamBinder struct {
+	binding *Binding
+	rec     *recorder
+}
+
+func (formMultipartStreamBinder) withBinding(b *Binding, rec *recorder) Binder {
+	return formMultipartStreamBinder{b, rec}
+}
+
+func (fb formMultipartStreamBinder) Bind(req *http.Request, obj interface{}) error {
+	value := reflect.ValueOf(obj)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return ErrBindNonPointerValue
+	}
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return err
+	}
+	if isMapTarget(obj) {
+		return fb.bindMap(reader, obj)
+	}
+	fields := make(map[string]streamField)
+	streamFields(value.Elem(), "", fields)
+
+	// Text fields are bound before the handler of a file part runs and once
+	// at the end, each pass only sets the fields of the keys which arrived
+	// since the previous one.
+	src := newStreamSource()
+	walk := &mappingWalk{used: make(map[string]bool)}
+	bind := func() error {
+		walk.remaining = nil
+		opt := setOptions{tag: "form", binding: fb.binding.orDefault(), rec: fb.rec, walk: walk}
+		_, err := mapping(value, emptyField, src, opt)
+		src.fresh = make(map[string]bool)
+		return err
+	}
+
+	bound := false
+	remaining := int64(defaultMemory)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name := part.FormName()
+		if name == "" {
+			continue
+		}
+		fb.rec.input("form", map[string][]string{name: nil})
+
+		if part.FileName() == "" {
+			data, err := io.ReadAll(io.LimitReader(part, remaining+1))
+			if err != nil {
+				return err
+			}
+			if remaining -= int64(len(data)); remaining < 0 {
+				return ErrMultipartValueTooLarge
+			}
+			src.form[name] = append(src.form[name], string(data))
+			src.fresh[name] = true
+			continue
+		}
+
+		field, ok := fields[name]
+		if !ok || field.value.Kind() == reflect.Func && field.value.IsNil() {
+			continue
+		}
+		// the fields sent before the file are visible to its handler
+		if len(src.fresh) > 0 || !bound {
+			if err := bind(); err != nil {
+				return err
+			}
+			bound = true
+		}
+		fb.rec.use("form", name)
+		fb.rec.set(field.path, "form", name, false)
+
+		if field.value.Type() == readerType {
+			field.value.Set(reflect.ValueOf(part))
+			walk.finish()
+			return nil
+		}
+		if err := field.value.Interface().(PartHandler)(part); err != nil {
+			return err
+		}
+	}
+	if len(src.fresh) > 0 || !bound {
+		if err := bind(); err != nil {
+			return err
+		}
+	}
+	walk.finish()
+	return nil
+}
+
+// bindMap binds the text fields of a multipart form to a map target, which
+// has no file fields, so the whole form is read first.
+func (fb formMultipartStreamBinder) bindMap(reader *multipart.Reader, obj interface{}) error {
+	form := make(map[string][]string)
+	remaining := int64(defaultMemory)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := part.FormName()
+		if name == "" || part.FileName() != "" {
+			continue
+		}
+		fb.rec.input("form", map[string][]string{name: nil})
+		data, err := io.ReadAll(io.LimitReader(part, remaining+1))
+		if err != nil {
+			return err
+		}
+		if remaining -= int64(len(data)); remaining < 0 {
+			return ErrMultipartValueTooLarge
+		}
+		form[name] = append(form[name], string(data))
+	}
+	return fb.binding.mapFormByTag(obj, form, "form", fb.rec)
+}
+
+// streamSource sets the fields of a streamed multipart form from the text
+// fields collected so far. A field is set on the first pass which visits it,
+// for its values or its default, and again only when keys for it are fresh,
+// when they arrived since the previous pass. Fresh keys set their fields
+// with all their values, so slices keep the values of earlier passes.
+type streamSource struct {
+	form    map[string][]string
+	fresh   map[string]bool
+	visited map[string]bool // by field path and key
+}
+
+func newStreamSource() *streamSource {
+	return &streamSource{
+		form:    make(map[string][]string),
+		fresh:   make(map[string]bool),
+		visited: make(map[string]bool),
+	}
+}
+
+var _ setter = (*streamSource)(nil)
+
+func (s *streamSource) TrySet(value reflect.Value, field reflect.StructField, key string, opt setOptions) (bool, error) {
+	id := opt.path + "\x00" + key
+	if s.visited[id] && !hasFormKey(s.fresh, key) {
+		return false, nil
+	}
+	s.visited[id] = true
+	return setByForm(value, field, s.form, key, opt)
+}
+
+func (s *streamSource) formValues() map[string][]string {
+	return s.form
+}
+
+// hasFormKey reports whether keys has key, keys nested under it, such as
+// "user[name]" or "user.name" for "user", or keys with its prefix when key
+// ends with "*".
+func hasFormKey[V any](keys map[string]V, key string) bool {
+	if _, ok := keys[key]; ok {
+		return true
+	}
+	prefix := strings.TrimSuffix(key, "*")
+	wildcard := prefix != key
+	for k := range keys {
+		if wildcard && strings.HasPrefix(k, prefix) ||
+			strings.HasPrefix(k, key+"[") || strings.HasPrefix(k, key+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// streamField is a struct field which receives file parts.
+type streamField struct {
+	value reflect.Value
+	path  string
+}
+
+// streamFields collects the PartHandler and io.Reader fields of value and of
+// its embedded structs by their form name.
+func streamFields(value reflect.Value, path string, fields map[string]streamField) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("form")
+		if tag == "-" || sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		if sf.Anonymous {
+			streamFields(value.Field(i), path, fields)
+			continue
+		}
+		if sf.Type != partHandlerType && sf.Type != readerType {
+			continue
+		}
+		name, _ := head(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = streamField{value.Field(i), joinFieldPath(path, sf.Name)}
+	}
+}
@@ -0,0 +1,185 @@
+package binding
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createRequestMultipartParts writes the files in order, those without a
+// filename as text fields.
+// createRequestMultipartParts builds a multipart form of text fields, the
+// parts without a Filename, and files.
+func createRequestMultipartParts(t *testing.T, files ...testFile) *http.Request {
+	parts := make([]testPart, len(files))
+	for i, file := range files {
+		parts[i] = testPart{Name: file.Fieldname, Filename: file.Filename, Content: string(file.Content)}
+		if file.Filename != "" {
+			parts[i].ContentType = "application/octet-stream"
+		}
+	}
+	return createRequestTypedParts(t, MIMEMultipartPOSTForm, parts...)
+}
+
+func TestFormMultipartStreamHandler(t *testing.T) {
+	type upload struct {
+		Title string      `form:"title"`
+		Tags  []string    `form:"tags"`
+		Size  int         `form:"size,default=10"`
+		Video PartHandler `form:"video"`
+		Other PartHandler
+	}
+
+	req := createRequestMultipartParts(t,
+		testFile{"title", "", []byte("holiday")},
+		testFile{"tags", "", []byte("a")},
+		testFile{"video", "holiday.mp4", []byte("video bytes")},
+		testFile{"tags", "", []byte("b")},
+		testFile{"skipped", "skipped.txt", []byte("nobody reads me")},
+	)
+
+	var s upload
+	var got []string
+	s.Video = func(part *multipart.Part) error {
+		data, err := io.ReadAll(part)
+		got = append(got, s.Title, part.FileName(), string(data))
+		return err
+	}
+	err := FormMultipartStream.Bind(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"holiday", "holiday.mp4", "video bytes"}, got)
+	assert.Equal(t, "holiday", s.Title)
+	assert.Equal(t, []string{"a", "b"}, s.Tags)
+	assert.Equal(t, 10, s.Size)
+
+	req = createRequestMultipartParts(t, testFile{"video", "a.mp4", []byte("x")})
+	s.Video = func(*multipart.Part) error { return errors.New("storage down") }
+	err = FormMultipartStream.Bind(req, &s)
+	assert.EqualError(t, err, "storage down")
+
+	req = requestWithBody("POST", "/", "title=x")
+	err = FormMultipartStream.Bind(req, &s)
+	assert.ErrorIs(t, err, http.ErrNotMultipart)
+	assert.ErrorIs(t, FormMultipartStream.Bind(req, s), ErrBindNonPointerValue)
+}
+
+// countingParams counts the passes which set it.
+type countingParams struct {
+	values []string
+	passes int
+}
+
+func (c *countingParams) UnmarshalParams(values []string) error {
+	c.values, c.passes = values, c.passes+1
+	return nil
+}
+
+func TestFormMultipartStreamRebind(t *testing.T) {
+	var s struct {
+		Tags   []string       `form:"tags"`
+		Note   countingParams `form:"note"`
+		Video  PartHandler    `form:"video"`
+		Poster PartHandler    `form:"poster"`
+	}
+
+	var seen [][]string
+	handler := func(part *multipart.Part) error {
+		seen = append(seen, append([]string(nil), s.Tags...))
+		return nil
+	}
+	s.Video, s.Poster = handler, handler
+
+	req := createRequestMultipartParts(t,
+		testFile{"tags", "", []byte("a")},
+		testFile{"note", "", []byte("n")},
+		testFile{"video", "a.mp4", []byte("x")},
+		testFile{"poster", "a.png", []byte("y")},
+		testFile{"tags", "", []byte("b")},
+		testFile{"poster", "b.png", []byte("z")},
+		testFile{"tags", "", []byte("c")},
+	)
+	assert.NoError(t, FormMultipartStream.Bind(req, &s))
+	assert.Equal(t, [][]string{{"a"}, {"a"}, {"a", "b"}}, seen)
+	assert.Equal(t, []string{"a", "b", "c"}, s.Tags)
+	assert.Equal(t, []string{"n"}, s.Note.values)
+	assert.Equal(t, 1, s.Note.passes) // only the pass after it arrived sets it
+
+	var more struct {
+		Size int `form:"size,default=10"`
+		User struct {
+			Name string `form:"name"`
+		} `form:"user"`
+		Rest  map[string][]string `form:",remaining"`
+		Video PartHandler         `form:"video"`
+	}
+	var sizes []int
+	more.Video = func(*multipart.Part) error {
+		sizes = append(sizes, more.Size)
+		return nil
+	}
+	req = createRequestMultipartParts(t,
+		testFile{"utm", "", []byte("a")},
+		testFile{"video", "a.mp4", []byte("x")},
+		testFile{"size", "", []byte("20")},
+		testFile{"user[name]", "", []byte("mike")},
+		testFile{"video", "b.mp4", []byte("y")},
+		testFile{"ref", "", []byte("b")},
+	)
+	assert.NoError(t, FormMultipartStream.Bind(req, &more))
+	assert.Equal(t, []int{10, 20}, sizes)
+	assert.Equal(t, 20, more.Size)
+	assert.Equal(t, "mike", more.User.Name)
+	assert.Equal(t, map[string][]string{"utm": {"a"}, "ref": {"b"}}, more.Rest)
+
+	m := map[string]string{}
+	req = createRequestMultipartParts(t, testFile{"a", "", []byte("1")}, testFile{"v", "v.mp4", []byte("x")})
+	assert.NoError(t, FormMultipartStream.Bind(req, &m))
+	assert.Equal(t, map[string]string{"a": "1"}, m)
+
+	seen, s.Tags, s.Note = nil, nil, countingParams{}
+	req = createRequestMultipartParts(t,
+		testFile{"tags", "", []byte("a")},
+		testFile{"video", "a.mp4", []byte("x")},
+		testFile{"poster", "a.png", []byte("y")},
+	)
+	assert.NoError(t, FormMultipartStream.Bind(req, &s))
+	assert.Equal(t, [][]string{{"a"}, {"a"}}, seen)
+	assert.Equal(t, []string{"a"}, s.Tags)
+}
+
+func TestFormMultipartStreamReader(t *testing.T) {
+	type upload struct {
+		Title string    `form:"title"`
+		File  io.Reader `form:"file"`
+		After string    `form:"after"`
+	}
+
+	req := createRequestMultipartParts(t,
+		testFile{"title", "", []byte("report")},
+		testFile{"file", "report.csv", []byte("a,b,c")},
+		testFile{"after", "", []byte("late")},
+	)
+
+	var s upload
+	err := New().BindWith(req, &s, FormMultipartStream)
+	assert.NoError(t, err)
+	assert.Equal(t, "report", s.Title)
+	assert.Empty(t, s.After)
+	data, err := io.ReadAll(s.File)
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b,c", string(data))
+}
+
+func TestFormMultipartStreamTooLarge(t *testing.T) {
+	var s struct {
+		Text string `form:"text"`
+	}
+	req := createRequestMultipartParts(t, testFile{"text", "", []byte(strings.Repeat("x", defaultMemory+1))})
+	err := FormMultipartStream.Bind(req, &s)
+	assert.ErrorIs(t, err, ErrMultipartValueTooLarge)
+}
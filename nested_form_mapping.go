@@ -0,0 +1,235 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MaxNestedFormDepth limits how many bracket or dot segments a nested form key
+// such as "user[address][city]" may have below the field it binds to.
+var MaxNestedFormDepth = 32
+
+// MaxNestedFormIndex limits the largest index accepted in a nested form key
+// such as "items[3][sku]", so a single key can not allocate a huge slice.
+var MaxNestedFormIndex = 1000
+
+var (
+	// ErrNestedFormDepth nested form key has more segments than MaxNestedFormDepth
+	ErrNestedFormDepth = errors.New("nested form key is too deep")
+
+	// ErrNestedFormIndex nested form key has an invalid or out of range index
+	ErrNestedFormIndex = errors.New("invalid index in nested form key")
+)
+
+// isNestable reports whether a value of type t can be filled from nested form
+// keys like "user[name]", "user.name", "meta[color]" or "items[0][sku]".
+func isNestable(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType || reflect.PtrTo(t).Implements(unmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) ||
+		reflect.PtrTo(t).Implements(optionalType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array:
+		return true
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String
+	}
+	return false
+}
+
+// nestedKeyHead splits the leading segment off the remainder of a nested key,
+// "[address][city]" gives "address" and "[city]", ".address.city" gives
+// "address" and ".city".
+func nestedKeyHead(rest string) (seg string, tail string, ok bool) {
+	switch {
+	case strings.HasPrefix(rest, "["):
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return "", "", false
+		}
+		seg, tail = rest[1:end], rest[end+1:]
+	case strings.HasPrefix(rest, "."):
+		rest = rest[1:]
+		end := strings.IndexAny(rest, ".[")
+		if end < 0 {
+			end = len(rest)
+		}
+		if end == 0 {
+			return "", "", false
+		}
+		seg, tail = rest[:end], rest[end:]
+	default:
+		return "", "", false
+	}
+	if tail != "" && tail[0] != '[' && tail[0] != '.' {
+		return "", "", false
+	}
+	return seg, tail, true
+}
+
+// nestedKeyDepth returns the number of segments in the remainder of a nested
+// key, ok is false when the remainder is malformed.
+func nestedKeyDepth(rest string) (depth int, ok bool) {
+	for rest != "" {
+		if _, rest, ok = nestedKeyHead(rest); !ok {
+			return 0, false
+		}
+		depth++
+	}
+	return depth, true
+}
+
+// nestedValues collects the form values whose keys are nested below prefix,
+// keyed by the remainder of the key, e.g. "user[name]" under "user" becomes "[name]".
+func nestedValues(form map[string][]string, prefix string) (map[string][]string, error) {
+	var nested map[string][]string
+	for key, vs := range form {
+		if len(key) <= len(prefix) || !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		rest := key[len(prefix):]
+		if rest[0] != '[' && rest[0] != '.' {
+			continue
+		}
+		depth, ok := nestedKeyDepth(rest)
+		if !ok {
+			continue
+		}
+		if depth > MaxNestedFormDepth {
+			return nil, fmt.Errorf("%w: %q", ErrNestedFormDepth, key)
+		}
+		if nested == nil {
+			nested = make(map[string][]string)
+		}
+		nested[rest] = vs
+	}
+	return nested, nil
+}
+
+// nestedGroup holds the nested values below one map key or slice index,
+// keyed by the rest of the key, along with their keys on the wire.
+type nestedGroup struct {
+	form     map[string][]string
+	wireKeys map[string]string
+}
+
+// groupNested groups the values nested below key by their leading segment.
+func groupNested(nested map[string][]string, key string, opt setOptions) map[string]nestedGroup {
+	groups := make(map[string]nestedGroup)
+	for rest, vs := range nested {
+		seg, tail, _ := nestedKeyHead(rest)
+		group, ok := groups[seg]
+		if !ok {
+			group = nestedGroup{make(map[string][]string), make(map[string]string)}
+			groups[seg] = group
+		}
+		group.form[tail] = vs
+		group.wireKeys[tail] = opt.wireKey(key + rest)
+	}
+	for seg, group := range groups {
+		if _, ok := group.wireKeys[""]; !ok { // the key of the element itself
+			group.wireKeys[""] = opt.wireKey(key) + "[" + seg + "]"
+		}
+	}
+	return groups
+}
+
+func setByNestedForm(value reflect.Value, field reflect.StructField, nested map[string][]string, key string, opt setOptions) (isSet bool, err error) {
+	opt.isDefaultExists = false
+
+	switch value.Kind() {
+	case reflect.Struct:
+		form := make(map[string][]string, len(nested))
+		wireKeys := make(map[string]string, len(nested))
+		for rest, vs := range nested {
+			seg, tail, _ := nestedKeyHead(rest)
+			form[seg+tail] = vs
+			wireKeys[seg+tail] = opt.wireKey(key + rest)
+		}
+		opt.wireKeys = wireKeys
+		return mapping(value, emptyField, formSource(form), opt)
+
+	case reflect.Map:
+		if value.IsNil() {
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+		for seg, group := range groupNested(nested, key, opt) {
+			elem := reflect.New(value.Type().Elem()).Elem()
+			if _, err := setNestedElem(elem, field, group, opt.path+"["+seg+"]", opt.mapEntry()); err != nil {
+				return false, conversionError(group.wireKeys[""], group.form[""], elem.Type(), err)
+			}
+			mapKey := reflect.New(value.Type().Key()).Elem()
+			mapKey.SetString(seg)
+			value.SetMapIndex(mapKey, elem)
+		}
+		return true, nil
+
+	case reflect.Slice, reflect.Array:
+		groups := groupNested(nested, key, opt)
+
+		var appended nestedGroup
+		if group, ok := groups[""]; ok {
+			if len(group.form) != 1 || group.form[""] == nil {
+				return false, fmt.Errorf("%w: %q", ErrNestedFormIndex, key+"[]")
+			}
+			appended = group
+			delete(groups, "")
+		}
+
+		length := 0
+		indexed := make(map[int]nestedGroup, len(groups))
+		for seg, group := range groups {
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i > MaxNestedFormIndex {
+				return false, fmt.Errorf("%w: %q", ErrNestedFormIndex, key+"["+seg+"]")
+			}
+			indexed[i] = group
+			if i >= length {
+				length = i + 1
+			}
+		}
+
+		vals := appended.form[""]
+		target := value
+		if value.Kind() == reflect.Slice {
+			target = reflect.MakeSlice(value.Type(), length+len(vals), length+len(vals))
+		} else if length+len(vals) > value.Len() {
+			return false, fmt.Errorf("%w: %q has more than %d elements", ErrNestedFormIndex, key, value.Len())
+		}
+
+		for i, group := range indexed {
+			if _, err := setNestedElem(target.Index(i), field, group, opt.path+"["+strconv.Itoa(i)+"]", opt); err != nil {
+				return false, err
+			}
+		}
+		for i, val := range vals {
+			group := nestedGroup{map[string][]string{"": {val}}, appended.wireKeys}
+			if _, err := setNestedElem(target.Index(length+i), field, group, opt.path+"["+strconv.Itoa(length+i)+"]", opt); err != nil {
+				return false, err
+			}
+		}
+
+		if value.Kind() == reflect.Slice {
+			value.Set(target)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func setNestedElem(value reflect.Value, field reflect.StructField, group nestedGroup, path string, opt setOptions) (isSet bool, err error) {
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value.Set(reflect.New(value.Type().Elem()))
+		}
+		value = value.Elem()
+	}
+	opt.path, opt.wireKeys = path, group.wireKeys
+	return setByForm(value, field, group.form, "", opt)
+}
@@ -0,0 +1,153 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nestedAddress struct {
+	City   string `form:"city" query:"city"`
+	Street string `form:"street" query:"street"`
+}
+
+type nestedUser struct {
+	Name    string         `form:"name" query:"name"`
+	Address *nestedAddress `form:"address" query:"address"`
+}
+
+func TestMappingNestedStruct(t *testing.T) {
+	var s struct {
+		User nestedUser `form:"user"`
+	}
+
+	err := mapForm(&s, map[string][]string{
+		"user[name]":             {"mike"},
+		"user[address][city]":    {"Berlin"},
+		"user.address.street":    {"Unter den Linden"},
+		"user[unknown][ignored]": {"x"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "mike", s.User.Name)
+	assert.Equal(t, "Berlin", s.User.Address.City)
+	assert.Equal(t, "Unter den Linden", s.User.Address.Street)
+}
+
+func TestMappingNestedMap(t *testing.T) {
+	var s struct {
+		Meta   map[string]string   `form:"meta"`
+		Tags   map[string][]string `form:"tags"`
+		Counts map[string]*int     `form:"counts"`
+	}
+
+	err := mapForm(&s, map[string][]string{
+		"meta[color]": {"red"},
+		"meta.size":   {"L"},
+		"tags[a]":     {"1", "2"},
+		"counts[x]":   {"3"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"color": "red", "size": "L"}, s.Meta)
+	assert.Equal(t, map[string][]string{"a": {"1", "2"}}, s.Tags)
+	assert.Equal(t, 3, *s.Counts["x"])
+}
+
+func TestMappingNestedSlice(t *testing.T) {
+	type item struct {
+		SKU string `form:"sku"`
+		Qty int    `form:"qty"`
+	}
+	var s struct {
+		Items  []item   `form:"items"`
+		Ptrs   []*item  `form:"ptrs"`
+		IDs    []int    `form:"ids"`
+		Index  []string `form:"index"`
+		Fixed  [2]int   `form:"fixed"`
+		Exists []int    `form:"exists"`
+	}
+
+	err := mapForm(&s, map[string][]string{
+		"items[0][sku]": {"A"},
+		"items[0][qty]": {"1"},
+		"items[1].sku":  {"B"},
+		"ptrs[0][sku]":  {"C"},
+		"ids[]":         {"1", "2", "3"},
+		"index[1]":      {"b"},
+		"index[0]":      {"a"},
+		"fixed[1]":      {"7"},
+		"exists":        {"5"},
+		"exists[0]":     {"6"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []item{{SKU: "A", Qty: 1}, {SKU: "B"}}, s.Items)
+	assert.Equal(t, []*item{{SKU: "C"}}, s.Ptrs)
+	assert.Equal(t, []int{1, 2, 3}, s.IDs)
+	assert.Equal(t, []string{"a", "b"}, s.Index)
+	assert.Equal(t, [2]int{0, 7}, s.Fixed)
+	assert.Equal(t, []int{5}, s.Exists)
+}
+
+func TestMappingNestedDefaultIgnored(t *testing.T) {
+	var s struct {
+		Page struct {
+			Size int `form:"size,default=20"`
+		} `form:"page"`
+	}
+
+	err := mapForm(&s, map[string][]string{"page[size]": {"5"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, s.Page.Size)
+
+	s.Page.Size = 0
+	err = mapForm(&s, map[string][]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 20, s.Page.Size)
+}
+
+func TestMappingNestedLimits(t *testing.T) {
+	defer func(depth, index int) {
+		MaxNestedFormDepth, MaxNestedFormIndex = depth, index
+	}(MaxNestedFormDepth, MaxNestedFormIndex)
+	MaxNestedFormDepth, MaxNestedFormIndex = 2, 10
+
+	var s struct {
+		M map[string]map[string]map[string]string `form:"m"`
+		L []int                                   `form:"l"`
+		A [1]int                                  `form:"a"`
+	}
+
+	err := mapForm(&s, map[string][]string{"m[a][b][c]": {"x"}})
+	assert.ErrorIs(t, err, ErrNestedFormDepth)
+
+	err = mapForm(&s, map[string][]string{"l[11]": {"1"}})
+	assert.ErrorIs(t, err, ErrNestedFormIndex)
+
+	err = mapForm(&s, map[string][]string{"l[x]": {"1"}})
+	assert.ErrorIs(t, err, ErrNestedFormIndex)
+
+	err = mapForm(&s, map[string][]string{"a[1]": {"1"}})
+	assert.ErrorIs(t, err, ErrNestedFormIndex)
+
+	err = mapForm(&s, map[string][]string{"l[10]": {"1"}})
+	assert.NoError(t, err)
+	assert.Len(t, s.L, 11)
+	assert.Equal(t, 1, s.L[10])
+}
+
+func TestBindingQueryNested(t *testing.T) {
+	var s struct {
+		User  nestedUser `query:"user"`
+		Items []struct {
+			SKU string `query:"sku"`
+		} `query:"items"`
+	}
+
+	req := requestWithBody("GET", "/?user[name]=mike&user.address.city=Berlin&items[0][sku]=A&items[1][sku]=B", "")
+	err := Query.Bind(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, "mike", s.User.Name)
+	assert.Equal(t, "Berlin", s.User.Address.City)
+	assert.Len(t, s.Items, 2)
+	assert.Equal(t, "A", s.Items[0].SKU)
+	assert.Equal(t, "B", s.Items[1].SKU)
+}
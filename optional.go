@@ -0,0 +1,107 @@
+package binding
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// Optional wraps a value of T and records whether it was present in the
+// request and whether it was explicitly null. An Optional field binds from
+// form, query, header, uri and JSON sources:
+//
+//	absent          IsSet() == false
+//	?limit=         IsSet() == true, IsNull() == true
+//	?limit=0        IsSet() == true, Value() == 0
+//	{"limit": null} IsSet() == true, IsNull() == true
+//
+// The default tag option stands in for an absent value, so an Optional with
+// one is set, query:"limit,default=20" binds an absent limit as Some(20).
+type Optional[T any] struct {
+	value T
+	set   bool
+	null  bool
+}
+
+// Some returns a present Optional holding v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{value: v, set: true}
+}
+
+// Null returns a present Optional which is explicitly null.
+func Null[T any]() Optional[T] {
+	return Optional[T]{set: true, null: true}
+}
+
+// IsSet reports whether the value was present in the request, or came from
+// the default tag option.
+func (o Optional[T]) IsSet() bool {
+	return o.set
+}
+
+// IsNull reports whether the value was present but empty or null.
+func (o Optional[T]) IsNull() bool {
+	return o.null
+}
+
+// Value returns the bound value, the zero value of T when absent or null.
+func (o Optional[T]) Value() T {
+	return o.value
+}
+
+// ValueOr returns the bound value, or def when absent or null.
+func (o Optional[T]) ValueOr(def T) T {
+	if !o.set || o.null {
+		return def
+	}
+	return o.value
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		o.setOptional(true)
+		return nil
+	}
+	if err := json.Unmarshal(data, &o.value); err != nil {
+		return err
+	}
+	o.setOptional(false)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.set || o.null {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.value)
+}
+
+func (o *Optional[T]) optionalElem() reflect.Value {
+	return reflect.ValueOf(&o.value).Elem()
+}
+
+func (o *Optional[T]) setOptional(null bool) {
+	o.set, o.null = true, null
+	if null {
+		var zero T
+		o.value = zero
+	}
+}
+
+// optional is implemented by *Optional[T], so the mappers can reach the
+// wrapped value without knowing T.
+type optional interface {
+	optionalElem() reflect.Value
+	setOptional(null bool)
+}
+
+var optionalType = reflect.TypeOf((*optional)(nil)).Elem()
+
+// optionalOf returns the optional implemented by a pointer to value.
+func optionalOf(value reflect.Value) (optional, bool) {
+	if !value.CanAddr() || !reflect.PtrTo(value.Type()).Implements(optionalType) {
+		return nil, false
+	}
+	return value.Addr().Interface().(optional), true
+}
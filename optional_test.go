@@ -0,0 +1,128 @@
+package binding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type optionalStruct struct {
+	Limit   Optional[int]        `query:"limit" header:"X-Limit" uri:"limit" json:"limit"`
+	Name    Optional[string]     `query:"name" json:"name"`
+	Since   Optional[time.Time]  `query:"since" time_format:"2006-01-02" time_utc:"1"`
+	IDs     Optional[[]int]      `query:"ids"`
+	Page    Optional[int]        `query:"page,default=1"`
+	Pointer *Optional[float64]   `query:"pointer"`
+	Nested  Optional[testUserID] `query:"nested"`
+}
+
+func TestOptionalQuery(t *testing.T) {
+	var s optionalStruct
+	req := requestWithBody("GET", "/?limit=0&name=&since=2019-01-20&ids=1&ids=2&pointer=1.5&nested=u-3", "")
+	err := Query.Bind(req, &s)
+	assert.NoError(t, err)
+
+	assert.True(t, s.Limit.IsSet())
+	assert.False(t, s.Limit.IsNull())
+	assert.Equal(t, 0, s.Limit.Value())
+
+	assert.True(t, s.Name.IsSet())
+	assert.True(t, s.Name.IsNull())
+	assert.Equal(t, "anonymous", s.Name.ValueOr("anonymous"))
+
+	assert.Equal(t, "2019-01-20 00:00:00 +0000 UTC", s.Since.Value().String())
+	assert.Equal(t, []int{1, 2}, s.IDs.Value())
+	assert.Equal(t, Some(1), s.Page)
+	assert.Equal(t, 1.5, s.Pointer.Value())
+	assert.Equal(t, testUserID(3), s.Nested.Value())
+
+	s = optionalStruct{}
+	req = requestWithBody("GET", "/", "")
+	err = Query.Bind(req, &s)
+	assert.NoError(t, err)
+	assert.False(t, s.Limit.IsSet())
+	assert.False(t, s.Limit.IsNull())
+	assert.Equal(t, 10, s.Limit.ValueOr(10))
+	assert.Nil(t, s.Pointer)
+
+	// the default option sets an absent value
+	assert.True(t, s.Page.IsSet())
+	assert.False(t, s.Page.IsNull())
+	assert.Equal(t, 1, s.Page.ValueOr(10))
+
+	s = optionalStruct{}
+	req = requestWithBody("GET", "/?page=3", "")
+	assert.NoError(t, Query.Bind(req, &s))
+	assert.Equal(t, Some(3), s.Page)
+
+	req = requestWithBody("GET", "/?limit=x", "")
+	err = Query.Bind(req, &s)
+	assert.Error(t, err)
+}
+
+func TestOptionalHeaderAndURI(t *testing.T) {
+	var s optionalStruct
+	req := requestWithBody("GET", "/", "")
+	req.Header.Set("X-Limit", "5")
+	err := Header.Bind(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, Some(5), s.Limit)
+
+	s = optionalStruct{}
+	err = URI.BindURI(map[string][]string{"limit": {""}}, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, Null[int](), s.Limit)
+}
+
+func TestOptionalJSON(t *testing.T) {
+	var s optionalStruct
+	err := JSON.(jsonBinder).BindBody([]byte(`{"limit": null, "name": "mike"}`), &s)
+	assert.NoError(t, err)
+	assert.Equal(t, Null[int](), s.Limit)
+	assert.Equal(t, Some("mike"), s.Name)
+	assert.False(t, s.Since.IsSet())
+
+	s = optionalStruct{}
+	err = JSON.(jsonBinder).BindBody([]byte(`{"limit": 0}`), &s)
+	assert.NoError(t, err)
+	assert.Equal(t, Some(0), s.Limit)
+	assert.False(t, s.Name.IsSet())
+
+	err = JSON.(jsonBinder).BindBody([]byte(`{"limit": "x"}`), &s)
+	assert.Error(t, err)
+
+	data, err := json.Marshal(struct {
+		A Optional[int]
+		B Optional[int]
+		C Optional[int]
+	}{A: Some(1), B: Null[int]()})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"A":1,"B":null,"C":null}`, string(data))
+}
+
+func TestMappingOmitEmpty(t *testing.T) {
+	var s struct {
+		Limit    int           `form:"limit,omitempty,default=20"`
+		Enabled  *bool         `form:"enabled,omitempty"`
+		Optional Optional[int] `form:"optional,omitempty"`
+		Names    []string      `form:"names,omitempty"`
+	}
+
+	err := mapForm(&s, map[string][]string{
+		"limit":    {""},
+		"enabled":  {""},
+		"optional": {""},
+		"names":    {""},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 20, s.Limit)
+	assert.Nil(t, s.Enabled)
+	assert.False(t, s.Optional.IsSet())
+	assert.Equal(t, []string{""}, s.Names)
+
+	err = mapForm(&s, map[string][]string{"limit": {"0"}, "enabled": {"false"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, s.Limit)
+	assert.False(t, *s.Enabled)
+}
@@ -0,0 +1,547 @@
+package binding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPatch patch document is not a valid JSON Patch or JSON Merge Patch
+	ErrInvalidPatch = errors.New("invalid patch document")
+)
+
+// PatchTestError is returned when a JSON Patch "test" operation fails.
+type PatchTestError struct {
+	Index int    // index of the operation in the patch document
+	Path  string // path the operation tested
+}
+
+func (e *PatchTestError) Error() string {
+	return fmt.Sprintf("json patch: test operation %d failed at %q", e.Index, e.Path)
+}
+
+// PatchPathError is returned when a JSON Patch operation refers to a path
+// which is not a valid JSON Pointer or does not exist in the target.
+type PatchPathError struct {
+	Index int    // index of the operation in the patch document
+	Op    string // the operation, such as "remove"
+	Path  string // the offending "path" or "from" member
+}
+
+func (e *PatchPathError) Error() string {
+	return fmt.Sprintf("json patch: %s operation %d has an invalid path %q", e.Op, e.Index, e.Path)
+}
+
+// errPatchPath is returned by the document operations below and turned into
+// a PatchPathError by applyJSONPatch.
+var errPatchPath = errors.New("invalid patch path")
+
+type mergePatchBinding struct {
+	binding *Binding
+	rec     *recorder
+}
+
+func (mergePatchBinding) withBinding(b *Binding, rec *recorder) Binder {
+	return mergePatchBinding{b, rec}
+}
+
+func (pb mergePatchBinding) Bind(req *http.Request, obj interface{}) error {
+	if req == nil || req.Body == nil || req.ContentLength == 0 {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return pb.BindBody(body, obj)
+}
+
+func (pb mergePatchBinding) BindBody(body []byte, obj interface{}) error {
+	if err := pb.binding.jsonLimits().check(body, "", obj); err != nil {
+		return err
+	}
+	return decodeMergePatch(pb.binding.jsonCodec(), bytes.NewReader(body), obj, pb.rec)
+}
+
+type jsonPatchBinding struct {
+	binding *Binding
+	rec     *recorder
+}
+
+func (jsonPatchBinding) withBinding(b *Binding, rec *recorder) Binder {
+	return jsonPatchBinding{b, rec}
+}
+
+func (pb jsonPatchBinding) Bind(req *http.Request, obj interface{}) error {
+	if req == nil || req.Body == nil || req.ContentLength == 0 {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return pb.BindBody(body, obj)
+}
+
+// BindBody applies the JSON Patch body to obj. The limits are checked with
+// no target, as the paths of the patch document are not those of obj.
+func (pb jsonPatchBinding) BindBody(body []byte, obj interface{}) error {
+	if err := pb.binding.jsonLimits().check(body, "", nil); err != nil {
+		return err
+	}
+	return decodeJSONPatch(pb.binding.jsonCodec(), bytes.NewReader(body), obj, pb.rec)
+}
+
+// decodeMergePatch applies the RFC 7396 JSON Merge Patch read from r to obj.
+// The keys of the patch are recorded as the fields they set.
+func decodeMergePatch(codec JSONCodec, r io.Reader, obj interface{}, rec *recorder) error {
+	patch, err := decodeJSONDoc(codec, r)
+	if err != nil {
+		return err
+	}
+	doc, err := jsonDocOf(codec, obj)
+	if err != nil {
+		return err
+	}
+	if err := setJSONDoc(codec, obj, mergePatch(doc, patch)); err != nil {
+		return err
+	}
+	if rec != nil {
+		rec.recordDoc(jsonFormat, patch, reflect.TypeOf(obj), "", "")
+	}
+	return nil
+}
+
+// decodeJSONPatch applies the RFC 6902 JSON Patch read from r to obj. The
+// paths the operations change are recorded as the fields they set.
+func decodeJSONPatch(codec JSONCodec, r io.Reader, obj interface{}, rec *recorder) error {
+	patch, err := decodeJSONDoc(codec, r)
+	if err != nil {
+		return err
+	}
+	ops, ok := patch.([]interface{})
+	if !ok {
+		return fmt.Errorf("%w: a JSON Patch must be an array of operations", ErrInvalidPatch)
+	}
+	doc, err := jsonDocOf(codec, obj)
+	if err != nil {
+		return err
+	}
+	if doc, err = applyJSONPatch(doc, ops, rec.recordPatch(reflect.TypeOf(obj))); err != nil {
+		return err
+	}
+	return setJSONDoc(codec, obj, doc)
+}
+
+// decodeJSONDoc decodes a JSON document with codec, keeping numbers as
+// json.Number so large integers survive the round trip.
+func decodeJSONDoc(codec JSONCodec, r io.Reader) (interface{}, error) {
+	decoder := codec.NewDecoder(r)
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// jsonDocOf returns the JSON document obj encodes to.
+func jsonDocOf(codec JSONCodec, obj interface{}) (interface{}, error) {
+	data, err := codec.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSONDoc(codec, bytes.NewReader(data))
+}
+
+// setJSONDoc makes obj hold the JSON document doc. Fields which have no key in
+// doc are reset first, as decoding only ever overwrites, fields ignored by
+// encoding/json keep their values.
+func setJSONDoc(codec JSONCodec, obj interface{}, doc interface{}) error {
+	value := reflect.ValueOf(obj)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return ErrBindNonPointerValue
+	}
+	resetJSONValue(value.Elem(), doc)
+
+	data, err := codec.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return codec.Unmarshal(data, obj)
+}
+
+func resetJSONValue(value reflect.Value, doc interface{}) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() && doc != nil {
+			resetJSONValue(value.Elem(), doc)
+		}
+	case reflect.Struct:
+		object, ok := doc.(map[string]interface{})
+		if !ok || isJSONLeaf(value.Type()) {
+			return
+		}
+		for _, sf := range jsonFormat.fields(value.Type()) {
+			fv, ok := fieldByIndex(value, sf.Index)
+			if !ok || !fv.CanSet() {
+				continue
+			}
+			if v, found := jsonLookup(object, jsonFormat.fieldName(sf)); found {
+				resetJSONValue(fv, v)
+			} else {
+				fv.Set(reflect.Zero(fv.Type()))
+			}
+		}
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Interface:
+		if value.CanSet() {
+			value.Set(reflect.Zero(value.Type()))
+		}
+	}
+}
+
+// fieldByIndex is reflect.Value.FieldByIndex which reports false instead of
+// panicking on a nil embedded pointer.
+func fieldByIndex(value reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return reflect.Value{}, false
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	return value, true
+}
+
+// jsonLookup finds key in object the way encoding/json matches field names,
+// exactly first, then case-insensitively.
+func jsonLookup(object map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := object[key]; ok {
+		return v, true
+	}
+	for k, v := range object {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// mergePatch applies patch to target as described in RFC 7396.
+func mergePatch(target, patch interface{}) interface{} {
+	p, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]interface{})
+	if !ok {
+		t = make(map[string]interface{}, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// applyJSONPatch applies the operations of an RFC 6902 JSON Patch to doc.
+// changed, when not nil, is called with the paths each applied operation
+// changes: the path, and the from of a move.
+func applyJSONPatch(doc interface{}, ops []interface{}, changed func(paths ...string)) (interface{}, error) {
+	for i, o := range ops {
+		op, ok := o.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: operation %d is not an object", ErrInvalidPatch, i)
+		}
+		name, _ := op["op"].(string)
+		path, ok := op["path"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: operation %d has no path", ErrInvalidPatch, i)
+		}
+		tokens, err := parseJSONPointer(path)
+		if err != nil {
+			return nil, &PatchPathError{Index: i, Op: name, Path: path}
+		}
+		value, hasValue := op["value"]
+
+		var from string
+		var fromTokens []string
+		if name == "move" || name == "copy" {
+			if from, ok = op["from"].(string); !ok {
+				return nil, fmt.Errorf("%w: %s operation %d has no from", ErrInvalidPatch, name, i)
+			}
+			if fromTokens, err = parseJSONPointer(from); err != nil {
+				return nil, &PatchPathError{Index: i, Op: name, Path: from}
+			}
+		} else if name != "remove" && !hasValue {
+			return nil, fmt.Errorf("%w: %s operation %d has no value", ErrInvalidPatch, name, i)
+		}
+
+		errPath := path
+		switch name {
+		case "add":
+			doc, err = addJSONValue(doc, tokens, value)
+		case "remove":
+			doc, _, err = removeJSONValue(doc, tokens)
+		case "replace":
+			doc, err = replaceJSONValue(doc, tokens, value)
+		case "move":
+			if strings.HasPrefix(path, from+"/") {
+				return nil, &PatchPathError{Index: i, Op: name, Path: path}
+			}
+			errPath = from
+			if doc, value, err = removeJSONValue(doc, fromTokens); err == nil {
+				errPath = path
+				doc, err = addJSONValue(doc, tokens, value)
+			}
+		case "copy":
+			errPath = from
+			if value, err = getJSONValue(doc, fromTokens); err == nil {
+				errPath = path
+				doc, err = addJSONValue(doc, tokens, copyJSONValue(value))
+			}
+		case "test":
+			var actual interface{}
+			if actual, err = getJSONValue(doc, tokens); err == nil && !equalJSONValue(actual, value) {
+				return nil, &PatchTestError{Index: i, Path: path}
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown operation %q", ErrInvalidPatch, name)
+		}
+		if errors.Is(err, errPatchPath) {
+			return nil, &PatchPathError{Index: i, Op: name, Path: errPath}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if changed != nil {
+			switch name {
+			case "move":
+				changed(from, path)
+			case "add", "remove", "replace", "copy":
+				changed(path)
+			}
+		}
+	}
+	return doc, nil
+}
+
+// updateJSONValue calls fn with the container holding the value tokens
+// refers to and the last token, and stores the container fn returns.
+func updateJSONValue(doc interface{}, tokens []string, fn func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(tokens) == 1 {
+		return fn(doc, tokens[0])
+	}
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		child, ok := container[tokens[0]]
+		if !ok {
+			return nil, errPatchPath
+		}
+		child, err := updateJSONValue(child, tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[tokens[0]] = child
+		return container, nil
+	case []interface{}:
+		i, err := jsonArrayIndex(tokens[0], len(container)-1)
+		if err != nil {
+			return nil, err
+		}
+		child, err := updateJSONValue(container[i], tokens[1:], fn)
+		if err != nil {
+			return nil, err
+		}
+		container[i] = child
+		return container, nil
+	}
+	return nil, errPatchPath
+}
+
+// jsonArrayIndex parses an array index token, which must not exceed max.
+func jsonArrayIndex(token string, max int) (int, error) {
+	if token == "" || len(token) > 1 && token[0] == '0' {
+		return 0, errPatchPath
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max {
+		return 0, errPatchPath
+	}
+	return i, nil
+}
+
+func getJSONValue(doc interface{}, tokens []string) (interface{}, error) {
+	for _, token := range tokens {
+		switch container := doc.(type) {
+		case map[string]interface{}:
+			v, ok := container[token]
+			if !ok {
+				return nil, errPatchPath
+			}
+			doc = v
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			doc = container[i]
+		default:
+			return nil, errPatchPath
+		}
+	}
+	return doc, nil
+}
+
+func addJSONValue(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return updateJSONValue(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			if token == "-" {
+				return append(container, value), nil
+			}
+			i, err := jsonArrayIndex(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			container = append(container, nil)
+			copy(container[i+1:], container[i:])
+			container[i] = value
+			return container, nil
+		}
+		return nil, errPatchPath
+	})
+}
+
+func removeJSONValue(doc interface{}, tokens []string) (newDoc interface{}, removed interface{}, err error) {
+	if len(tokens) == 0 {
+		return nil, doc, nil
+	}
+	newDoc, err = updateJSONValue(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			v, ok := container[token]
+			if !ok {
+				return nil, errPatchPath
+			}
+			removed = v
+			delete(container, token)
+			return container, nil
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			removed = container[i]
+			return append(container[:i], container[i+1:]...), nil
+		}
+		return nil, errPatchPath
+	})
+	return newDoc, removed, err
+}
+
+func replaceJSONValue(doc interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+	return updateJSONValue(doc, tokens, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok {
+				return nil, errPatchPath
+			}
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			i, err := jsonArrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, err
+			}
+			container[i] = value
+			return container, nil
+		}
+		return nil, errPatchPath
+	})
+}
+
+func copyJSONValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			c[k] = copyJSONValue(v)
+		}
+		return c
+	case []interface{}:
+		c := make([]interface{}, len(value))
+		for i, v := range value {
+			c[i] = copyJSONValue(v)
+		}
+		return c
+	}
+	return value
+}
+
+// jsonNumber is implemented by the number types of encoding/json and jsoniter.
+type jsonNumber interface {
+	Float64() (float64, error)
+	String() string
+}
+
+// equalJSONValue compares two JSON values as RFC 6902 "test" does, numbers
+// are equal when their values are.
+func equalJSONValue(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || !equalJSONValue(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSONValue(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case jsonNumber:
+		b, ok := b.(jsonNumber)
+		if !ok {
+			return false
+		}
+		if a.String() == b.String() {
+			return true
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	}
+	return a == b
+}
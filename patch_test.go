@@ -0,0 +1,227 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type patchAddress struct {
+	City string `json:"city"`
+	Zip  string `json:"zip,omitempty"`
+}
+
+type patchUser struct {
+	Name    string            `json:"name"`
+	Email   string            `json:"email,omitempty"`
+	Age     int               `json:"age"`
+	Tags    []string          `json:"tags"`
+	Address *patchAddress     `json:"address,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Secret  string            `json:"-"`
+}
+
+func newPatchUser() patchUser {
+	return patchUser{
+		Name:    "mike",
+		Email:   "mike@example.com",
+		Age:     30,
+		Tags:    []string{"a", "b"},
+		Address: &patchAddress{City: "Paris", Zip: "75001"},
+		Labels:  map[string]string{"team": "core"},
+		Secret:  "s3cr3t",
+	}
+}
+
+func TestBindingMergePatch(t *testing.T) {
+	user := newPatchUser()
+	req := requestWithBody("PATCH", "/", `{"name":"john","email":null,"address":{"zip":null},"labels":{"role":"admin"}}`)
+	req.Header.Set("Content-Type", MIMEMergePatchJSON)
+	err := Bind(req, &user)
+	assert.NoError(t, err)
+	assert.Equal(t, "john", user.Name)
+	assert.Empty(t, user.Email)
+	assert.Equal(t, 30, user.Age)
+	assert.Equal(t, []string{"a", "b"}, user.Tags)
+	assert.Equal(t, &patchAddress{City: "Paris"}, user.Address)
+	assert.Equal(t, map[string]string{"team": "core", "role": "admin"}, user.Labels)
+	assert.Equal(t, "s3cr3t", user.Secret)
+
+	// arrays are replaced as a whole
+	err = MergePatch.(mergePatchBinding).BindBody([]byte(`{"tags":["c"],"address":null}`), &user)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, user.Tags)
+	assert.Nil(t, user.Address)
+
+	m := map[string]interface{}{"a": "b", "c": map[string]interface{}{"d": "e"}}
+	err = MergePatch.(mergePatchBinding).BindBody([]byte(`{"a":"z","c":{"d":null,"f":1}}`), &m)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "z", "c": map[string]interface{}{"f": float64(1)}}, m)
+
+	err = MergePatch.(mergePatchBinding).BindBody([]byte(`{"age":"x"}`), &user)
+	assert.Error(t, err)
+	err = MergePatch.(mergePatchBinding).BindBody([]byte(`{`), &user)
+	assert.Error(t, err)
+}
+
+func TestBindingJSONPatch(t *testing.T) {
+	user := newPatchUser()
+	req := requestWithBody("PATCH", "/", `[
+		{"op":"test","path":"/name","value":"mike"},
+		{"op":"replace","path":"/name","value":"john"},
+		{"op":"add","path":"/tags/1","value":"x"},
+		{"op":"add","path":"/tags/-","value":"z"},
+		{"op":"remove","path":"/email"},
+		{"op":"copy","from":"/address/city","path":"/labels/city"},
+		{"op":"move","from":"/address/zip","path":"/labels/zip"},
+		{"op":"test","path":"/age","value":30.0}
+	]`)
+	req.Header.Set("Content-Type", MIMEJSONPatch)
+	err := Bind(req, &user)
+	assert.NoError(t, err)
+	assert.Equal(t, "john", user.Name)
+	assert.Empty(t, user.Email)
+	assert.Equal(t, []string{"a", "x", "b", "z"}, user.Tags)
+	assert.Equal(t, &patchAddress{City: "Paris"}, user.Address)
+	assert.Equal(t, map[string]string{"team": "core", "city": "Paris", "zip": "75001"}, user.Labels)
+	assert.Equal(t, "s3cr3t", user.Secret)
+
+	m := map[string]interface{}{"a/b": "c", "m~n": "o"}
+	err = JSONPatch.(jsonPatchBinding).BindBody([]byte(`[{"op":"move","from":"/a~1b","path":"/m~0n"}]`), &m)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"m~n": "c"}, m)
+}
+
+func TestBindingJSONPatchErrors(t *testing.T) {
+	user := newPatchUser()
+	bind := func(patch string) error {
+		return JSONPatch.(jsonPatchBinding).BindBody([]byte(patch), &user)
+	}
+
+	err := bind(`[{"op":"replace","path":"/name","value":"john"},{"op":"test","path":"/name","value":"mike"}]`)
+	var testErr *PatchTestError
+	assert.ErrorAs(t, err, &testErr)
+	assert.Equal(t, &PatchTestError{Index: 1, Path: "/name"}, testErr)
+	// a failed patch leaves the target untouched
+	assert.Equal(t, "mike", user.Name)
+
+	var pathErr *PatchPathError
+	for _, tt := range []struct {
+		patch string
+		err   *PatchPathError
+	}{
+		{`[{"op":"remove","path":"/missing"}]`, &PatchPathError{Index: 0, Op: "remove", Path: "/missing"}},
+		{`[{"op":"replace","path":"name","value":1}]`, &PatchPathError{Index: 0, Op: "replace", Path: "name"}},
+		{`[{"op":"add","path":"/tags/5","value":"x"}]`, &PatchPathError{Index: 0, Op: "add", Path: "/tags/5"}},
+		{`[{"op":"add","path":"/tags/01","value":"x"}]`, &PatchPathError{Index: 0, Op: "add", Path: "/tags/01"}},
+		{`[{"op":"move","from":"/nothing","path":"/name"}]`, &PatchPathError{Index: 0, Op: "move", Path: "/nothing"}},
+		{`[{"op":"move","from":"/address","path":"/address/city"}]`, &PatchPathError{Index: 0, Op: "move", Path: "/address/city"}},
+		{`[{"op":"test","path":"/a/b","value":1}]`, &PatchPathError{Index: 0, Op: "test", Path: "/a/b"}},
+	} {
+		err = bind(tt.patch)
+		if assert.ErrorAs(t, err, &pathErr, tt.patch) {
+			assert.Equal(t, tt.err, pathErr)
+		}
+	}
+
+	for _, patch := range []string{
+		`{"op":"remove","path":"/name"}`,
+		`[1]`,
+		`[{"op":"remove"}]`,
+		`[{"op":"add","path":"/name"}]`,
+		`[{"op":"copy","path":"/name"}]`,
+		`[{"op":"frobnicate","path":"/name"}]`,
+	} {
+		assert.ErrorIs(t, bind(patch), ErrInvalidPatch, patch)
+	}
+}
+
+func TestBindingPatchJSONLimits(t *testing.T) {
+	b := New()
+	b.SetJSONLimits(JSONLimits{RejectDuplicateKeys: true, MaxDepth: 2})
+
+	for body, want := range map[string]error{
+		`{"name": "john", "name": "admin"}`: ErrJSONDuplicateKey,
+		`{"name": "john", "Name": "admin"}`: ErrJSONDuplicateKey,
+		`{"labels": {"a": {"b": "c"}}}`:     ErrJSONTooDeep,
+	} {
+		user := newPatchUser()
+		req := requestWithBody("PATCH", "/", body)
+		req.Header.Set("Content-Type", MIMEMergePatchJSON)
+		assert.ErrorIs(t, b.Bind(req, &user), want, body)
+		assert.Equal(t, "mike", user.Name, body)
+	}
+
+	for body, want := range map[string]error{
+		`[{"op": "replace", "path": "/name", "value": "john", "value": "admin"}]`: ErrJSONDuplicateKey,
+		`[{"op": "add", "path": "/labels", "value": {"a": "b"}}]`:                 ErrJSONTooDeep,
+	} {
+		user := newPatchUser()
+		req := requestWithBody("PATCH", "/", body)
+		req.Header.Set("Content-Type", MIMEJSONPatch)
+		assert.ErrorIs(t, b.Bind(req, &user), want, body)
+		assert.Equal(t, "mike", user.Name, body)
+	}
+
+	user := newPatchUser()
+	req := requestWithBody("PATCH", "/", `{"name": "john", "labels": {"a": "b"}}`)
+	req.Header.Set("Content-Type", MIMEMergePatchJSON)
+	assert.NoError(t, b.Bind(req, &user))
+	assert.Equal(t, "john", user.Name)
+}
+
+func TestBindingPatchEmptyBody(t *testing.T) {
+	for _, contentType := range []string{MIMEMergePatchJSON, MIMEJSONPatch} {
+		user := newPatchUser()
+		req := requestWithBody("PATCH", "/", "")
+		req.Header.Set("Content-Type", contentType)
+		assert.NoError(t, Bind(req, &user), contentType)
+		assert.Equal(t, newPatchUser(), user, contentType)
+
+		req.Body = nil
+		assert.NoError(t, Bind(req, &user), contentType)
+		assert.NoError(t, binders[contentType].Bind(nil, &user), contentType)
+	}
+}
+
+func TestBindingPatchWithResult(t *testing.T) {
+	user := newPatchUser()
+	req := requestWithBody("PATCH", "/", `{"name": "john", "email": null, "address": {"zip": "1"}}`)
+	req.Header.Set("Content-Type", MIMEMergePatchJSON)
+	result, err := BindWithResult(req, &user)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Address", "Address.Zip", "Email", "Name"}, result.Paths())
+	assert.Equal(t, FieldSource{Source: "json", Key: "/address/zip"}, result.Fields["Address.Zip"])
+
+	user = newPatchUser()
+	req = requestWithBody("PATCH", "/", `[
+		{"op": "test", "path": "/age", "value": 30},
+		{"op": "replace", "path": "/name", "value": "john"},
+		{"op": "add", "path": "/tags/-", "value": "c"},
+		{"op": "move", "from": "/address/zip", "path": "/labels/zip"}
+	]`)
+	req.Header.Set("Content-Type", MIMEJSONPatch)
+	result, err = BindWithResult(req, &user)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Address", "Address.Zip", "Labels", "Labels[zip]", "Name", "Tags"}, result.Paths())
+	assert.Equal(t, FieldSource{Source: "json", Key: "/labels/zip"}, result.Fields["Labels[zip]"])
+}
+
+func TestBindingPatchStrict(t *testing.T) {
+	b := New()
+	b.SetStrict("json")
+
+	user := newPatchUser()
+	req := requestWithBody("PATCH", "/", `{"nme": "john"}`)
+	req.Header.Set("Content-Type", MIMEMergePatchJSON)
+	assert.EqualError(t, b.Bind(req, &user), `unknown parameters: json "/nme"`)
+
+	req = requestWithBody("PATCH", "/", `[{"op": "add", "path": "/nme", "value": "john"}]`)
+	req.Header.Set("Content-Type", MIMEJSONPatch)
+	assert.EqualError(t, b.Bind(req, &user), `unknown parameters: json "/nme"`)
+
+	req = requestWithBody("PATCH", "/", `[{"op": "replace", "path": "/name", "value": "john"}]`)
+	req.Header.Set("Content-Type", MIMEJSONPatch)
+	assert.NoError(t, b.Bind(req, &user))
+	assert.Equal(t, "john", user.Name)
+}
@@ -2,9 +2,17 @@ package binding
 
 import "net/http"
 
-type queryBinding struct{}
+type queryBinding struct {
+	binding *Binding
+	rec     *recorder
+}
+
+func (queryBinding) withBinding(b *Binding, rec *recorder) Binder {
+	return queryBinding{b, rec}
+}
 
-func (queryBinding) Bind(req *http.Request, obj interface{}) error {
+func (qb queryBinding) Bind(req *http.Request, obj interface{}) error {
 	values := req.URL.Query()
-	return mapFormWithTag(obj, values, "query")
+	qb.rec.input("query", values)
+	return qb.binding.mapFormByTag(obj, values, "query", qb.rec)
 }
@@ -0,0 +1,82 @@
+package binding
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrRemainingType field with the remaining tag option is not a url.Values, http.Header or map[string][]string
+var ErrRemainingType = errors.New("remaining field must be url.Values, http.Header or map[string][]string")
+
+var valuesType = reflect.TypeOf(map[string][]string(nil))
+
+// mappingWalk is the state shared by a whole mapping pass over a struct,
+// embedded and nested structs included.
+type mappingWalk struct {
+	used      map[string]bool // wire keys consumed by a field
+	remaining []remainingField
+}
+
+// remainingField is a field with the remaining tag option, such as
+// query:",remaining", set once the walk knows which keys no field consumed.
+type remainingField struct {
+	value reflect.Value
+	form  map[string][]string
+	opt   setOptions
+}
+
+// formValues is implemented by the setters whose parameters a remaining
+// field can receive.
+type formValues interface {
+	formValues() map[string][]string
+}
+
+func (form formSource) formValues() map[string][]string {
+	return form
+}
+
+func (hs headerSource) formValues() map[string][]string {
+	return hs
+}
+
+func (r *multipartRequest) formValues() map[string][]string {
+	return r.MultipartForm.Value
+}
+
+// use marks the wire key as consumed.
+func (w *mappingWalk) use(key string) {
+	if w != nil {
+		w.used[key] = true
+	}
+}
+
+// setRemaining defers a remaining field to the end of the walk.
+func (w *mappingWalk) setRemaining(value reflect.Value, setter setter, opt setOptions) (bool, error) {
+	fv, ok := setter.(formValues)
+	if !ok || w == nil {
+		return false, nil
+	}
+	if !valuesType.ConvertibleTo(value.Type()) {
+		return false, ErrRemainingType
+	}
+	w.remaining = append(w.remaining, remainingField{value, fv.formValues(), opt})
+	return true, nil
+}
+
+// finish sets the remaining fields to the parameters no field consumed.
+func (w *mappingWalk) finish() {
+	for _, field := range w.remaining {
+		remaining := make(map[string][]string)
+		for key, vs := range field.form {
+			wire := field.opt.wireKey(key)
+			if !w.used[wire] {
+				remaining[wire] = vs
+				field.opt.rec.use(field.opt.tag, wire)
+			}
+		}
+		field.value.Set(reflect.ValueOf(remaining).Convert(field.value.Type()))
+		if len(remaining) > 0 {
+			field.opt.rec.set(field.opt.path, field.opt.tag, "", false)
+		}
+	}
+}
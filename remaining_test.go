@@ -0,0 +1,60 @@
+package binding
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type remainingPaging struct {
+	Page int `query:"page" form:"page"`
+}
+
+func TestMappingRemaining(t *testing.T) {
+	var s struct {
+		remainingPaging
+		Query string `query:"q"`
+		User  struct {
+			Name string `query:"name"`
+		} `query:"user"`
+		Rest   url.Values  `query:",remaining"`
+		Header http.Header `header:",remaining"`
+		Token  string      `header:"X-Token"`
+	}
+
+	req := requestWithBody("GET", "/?page=2&q=go&user[name]=mike&user[age]=30&sort=asc&sort=desc&debug=", "")
+	req.Header.Set("X-Token", "t")
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+
+	result, err := BindWithResult(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, s.Page)
+	assert.Equal(t, "go", s.Query)
+	assert.Equal(t, "mike", s.User.Name)
+	assert.Equal(t, url.Values{"sort": {"asc", "desc"}, "debug": {""}, "user[age]": {"30"}}, s.Rest)
+	assert.Equal(t, http.Header{"X-Forwarded-For": {"10.0.0.1"}}, s.Header)
+	assert.Equal(t, "t", s.Token)
+	assert.True(t, result.IsSet("Rest"))
+	assert.Empty(t, result.Ignored)
+
+	// a strict Binding accepts what a remaining field takes
+	b := New()
+	b.SetStrict("query")
+	assert.NoError(t, b.Bind(req, &s))
+
+	var form struct {
+		Name string              `form:"name"`
+		Rest map[string][]string `form:",remaining"`
+	}
+	req = requestWithBody("POST", "/", "name=a&x=1&y=2")
+	req.Header.Set("Content-Type", MIMEPOSTForm)
+	assert.NoError(t, Bind(req, &form))
+	assert.Equal(t, map[string][]string{"x": {"1"}, "y": {"2"}}, form.Rest)
+
+	var invalid struct {
+		Rest map[string]string `query:",remaining"`
+	}
+	assert.ErrorIs(t, Query.Bind(requestWithBody("GET", "/?a=1", ""), &invalid), ErrRemainingType)
+}
@@ -0,0 +1,299 @@
+package binding
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// BindResult reports which fields a BindWithResult call populated and where
+// their values came from.
+type BindResult struct {
+	// Fields maps the path of every populated field, such as "Page",
+	// "User.Address.City" or "Items[0].SKU", to the source of its value.
+	Fields map[string]FieldSource
+
+	// Ignored lists the request inputs which no field consumed, sorted by
+	// source and key.
+	Ignored []Input
+
+	// Coercions lists the values lenient JSON decoding converted to the types
+	// of their fields, sorted by path.
+	Coercions []Coercion
+}
+
+// FieldSource describes where the value of a field came from.
+type FieldSource struct {
+	// Source is the tag the field was bound by: "query", "form", "header",
+	// "uri", "part", "json", "xml", "yaml", "toml", or "body" for the raw
+	// body.
+	Source string
+
+	// Key is the key on the wire, a JSON Pointer such as "/user/name" for
+	// JSON, XML, YAML and TOML bodies.
+	Key string
+
+	// Default is set when the value came from the default tag option.
+	Default bool
+}
+
+// Input identifies a request input by its source and key.
+type Input struct {
+	Source string
+	Key    string
+}
+
+// IsSet reports whether the field at path was populated.
+func (r *BindResult) IsSet(path string) bool {
+	_, ok := r.Fields[path]
+	return ok
+}
+
+// Paths returns the sorted paths of the populated fields.
+func (r *BindResult) Paths() []string {
+	paths := make([]string, 0, len(r.Fields))
+	for path := range r.Fields {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Defaults returns the sorted paths of the fields set from a default value.
+func (r *BindResult) Defaults() []string {
+	var paths []string
+	for path, src := range r.Fields {
+		if src.Default {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// BindWithResult binds like Bind and reports what it populated.
+func BindWithResult(req *http.Request, obj interface{}, params ...map[string][]string) (*BindResult, error) {
+	return defaultBinding.BindWithResult(req, obj, params...)
+}
+
+// BindWithResult binds like Bind and reports what it populated.
+func (b *Binding) BindWithResult(req *http.Request, obj interface{}, params ...map[string][]string) (*BindResult, error) {
+	rec := newRecorder()
+	if err := b.bind(req, obj, rec, params...); err != nil {
+		return nil, err
+	}
+	return rec.bindResult(), nil
+}
+
+// recorder tracks the inputs of a single Bind call and the fields they
+// populated. A nil recorder records nothing.
+type recorder struct {
+	fields    map[string]FieldSource
+	inputs    map[Input]bool // true once consumed
+	coercions map[string]Coercion
+	prefix    string
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		fields:    make(map[string]FieldSource),
+		inputs:    make(map[Input]bool),
+		coercions: make(map[string]Coercion),
+	}
+}
+
+// input registers the keys of values as inputs of source.
+func (rec *recorder) input(source string, values map[string][]string) {
+	if rec == nil {
+		return
+	}
+	for key := range values {
+		in := Input{source, key}
+		if _, ok := rec.inputs[in]; !ok {
+			rec.inputs[in] = false
+		}
+	}
+}
+
+// use marks key as consumed by a field bound by tag. Form fields consume
+// query keys too, as the form binder reads both the body and the query.
+func (rec *recorder) use(tag, key string) {
+	if rec == nil {
+		return
+	}
+	rec.inputs[Input{tag, key}] = true
+	if tag == "form" {
+		rec.inputs[Input{"query", key}] = true
+	}
+}
+
+func (rec *recorder) set(path, tag, key string, isDefault bool) {
+	if rec == nil || path == "" {
+		return
+	}
+	rec.fields[joinFieldPath(rec.prefix, path)] = FieldSource{Source: tag, Key: key, Default: isDefault}
+}
+
+// at returns a recorder sharing the fields and inputs of rec which records
+// the paths of a value bound at path.
+func (rec *recorder) at(path string) *recorder {
+	if rec == nil || path == "" {
+		return rec
+	}
+	return &recorder{fields: rec.fields, inputs: rec.inputs, coercions: rec.coercions, prefix: joinFieldPath(rec.prefix, path)}
+}
+
+func (rec *recorder) bindResult() *BindResult {
+	result := &BindResult{Fields: rec.fields}
+	for in, used := range rec.inputs {
+		if !used {
+			result.Ignored = append(result.Ignored, in)
+		}
+	}
+	sortInputs(result.Ignored)
+	for _, c := range rec.coercions {
+		result.Coercions = append(result.Coercions, c)
+	}
+	sortCoercions(result.Coercions)
+	return result
+}
+
+func sortInputs(inputs []Input) {
+	sort.Slice(inputs, func(i, j int) bool {
+		a, b := inputs[i], inputs[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Key < b.Key
+	})
+}
+
+// wireKey returns the key on the wire for a key of the current form, which
+// differs for the sub forms of nested keys.
+func (opt setOptions) wireKey(key string) string {
+	if wire, ok := opt.wireKeys[key]; ok {
+		return wire
+	}
+	return key
+}
+
+// use marks the form key as consumed.
+func (opt setOptions) use(key string) {
+	opt.walk.use(opt.wireKey(key))
+	opt.rec.use(opt.tag, opt.wireKey(key))
+}
+
+// record records the current field as set from the form key.
+func (opt setOptions) record(key string, isDefault bool) {
+	opt.rec.set(opt.path, opt.tag, opt.wireKey(key), isDefault)
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// recordJSON records the fields of obj populated by the JSON document data,
+// the value at pointer of the body, decoded with codec.
+func (rec *recorder) recordJSON(codec JSONCodec, data []byte, obj interface{}, pointer string) error {
+	if rec == nil {
+		return nil
+	}
+	var doc interface{}
+	if err := codec.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	rec.recordDoc(jsonFormat, doc, reflect.TypeOf(obj), "", pointer)
+	return nil
+}
+
+// recordPatch returns a function recording the fields of t which the JSON
+// Pointers of JSON Patch operations change, nil when rec records nothing.
+func (rec *recorder) recordPatch(t reflect.Type) func(paths ...string) {
+	if rec == nil {
+		return nil
+	}
+	return func(paths ...string) {
+		for _, path := range paths {
+			rec.recordPointer(jsonFormat, t, path)
+		}
+	}
+}
+
+// recordPointer records the fields of t along the JSON Pointer pointer, as
+// recordDoc does for a document with a value there. A key no field takes is
+// recorded as an input.
+func (rec *recorder) recordPointer(format *bodyFormat, t reflect.Type, pointer string) {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return
+	}
+	var path, key string
+	for _, token := range tokens {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		key += "/" + escapeJSONPointer(token)
+		switch {
+		case t.Kind() == reflect.Struct && !format.leaf(t):
+			sf, ok := format.field(t, token)
+			if !ok {
+				rec.input(format.name, map[string][]string{key: nil})
+				return
+			}
+			path, t = joinFieldPath(path, sf.Name), sf.Type
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String,
+			(t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && token != "-":
+			path, t = path+"["+token+"]", t.Elem()
+		default:
+			return
+		}
+		rec.set(path, format.name, key, false)
+	}
+}
+
+// recordDoc records the fields of t populated by doc, a document of format
+// decoded into maps and slices. Keys are JSON Pointers, whatever the format.
+func (rec *recorder) recordDoc(format *bodyFormat, doc interface{}, t reflect.Type, path, pointer string) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		switch {
+		case t.Kind() == reflect.Struct && !format.leaf(t):
+			for key, v := range doc {
+				keyPointer := pointer + "/" + escapeJSONPointer(key)
+				sf, ok := format.field(t, key)
+				if !ok {
+					rec.input(format.name, map[string][]string{keyPointer: nil})
+					continue
+				}
+				fieldPath := joinFieldPath(path, sf.Name)
+				rec.set(fieldPath, format.name, keyPointer, false)
+				rec.recordDoc(format, v, sf.Type, fieldPath, keyPointer)
+			}
+		case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+			for key, v := range doc {
+				keyPath := path + "[" + key + "]"
+				keyPointer := pointer + "/" + escapeJSONPointer(key)
+				rec.set(keyPath, format.name, keyPointer, false)
+				rec.recordDoc(format, v, t.Elem(), keyPath, keyPointer)
+			}
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for i, v := range doc {
+			elemPath := path + "[" + strconv.Itoa(i) + "]"
+			elemPointer := pointer + "/" + strconv.Itoa(i)
+			rec.set(elemPath, format.name, elemPointer, false)
+			rec.recordDoc(format, v, t.Elem(), elemPath, elemPointer)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package binding
+
+import (
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindWithResult(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+	var s struct {
+		Page     int               `query:"page,default=1"`
+		PageSize int               `query:"page_size"`
+		Filter   map[string]string `query:"filter"`
+		Trace    string            `header:"X-Trace"`
+		ID       int               `uri:"id"`
+		Name     string            `json:"name"`
+		Content  *string           `json:"content"`
+		Address  address           `json:"address"`
+		Tags     []string          `json:"tags"`
+	}
+
+	req := requestWithBody(http.MethodPost, "/?page_size=10&filter.color=red&utm_source=mail",
+		`{"name": "mike", "address": {"city": "Berlin"}, "tags": ["a"], "admin": true}`)
+	req.Header.Set("Content-Type", MIMEJSON)
+	req.Header.Set("X-Trace", "abc")
+
+	result, err := BindWithResult(req, &s, map[string][]string{"id": {"7"}})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, s.Page)
+	assert.Equal(t, "Berlin", s.Address.City)
+
+	assert.Equal(t, []string{
+		"Address", "Address.City", "Filter", "Filter[color]", "ID", "Name",
+		"Page", "PageSize", "Tags", "Tags[0]", "Trace",
+	}, result.Paths())
+	assert.Equal(t, []string{"Page"}, result.Defaults())
+
+	assert.True(t, result.IsSet("Name"))
+	assert.False(t, result.IsSet("Content"))
+
+	assert.Equal(t, FieldSource{Source: "query", Key: "page", Default: true}, result.Fields["Page"])
+	assert.Equal(t, FieldSource{Source: "query", Key: "page_size"}, result.Fields["PageSize"])
+	assert.Equal(t, FieldSource{Source: "query", Key: "filter.color"}, result.Fields["Filter[color]"])
+	assert.Equal(t, FieldSource{Source: "header", Key: "X-Trace"}, result.Fields["Trace"])
+	assert.Equal(t, FieldSource{Source: "uri", Key: "id"}, result.Fields["ID"])
+	assert.Equal(t, FieldSource{Source: "json", Key: "/address/city"}, result.Fields["Address.City"])
+
+	assert.Contains(t, result.Ignored, Input{"json", "/admin"})
+	assert.Contains(t, result.Ignored, Input{"query", "utm_source"})
+	assert.Contains(t, result.Ignored, Input{"header", "Content-Type"})
+	assert.NotContains(t, result.Ignored, Input{"query", "page_size"})
+	assert.NotContains(t, result.Ignored, Input{"header", "X-Trace"})
+}
+
+func TestBindWithResultNestedForm(t *testing.T) {
+	var s struct {
+		User struct {
+			Name string `form:"name"`
+		} `form:"user"`
+		Items []struct {
+			SKU string `form:"sku"`
+		} `form:"items"`
+		Avatar string `form:"avatar"`
+	}
+
+	req := requestWithBody(http.MethodPost, "/", "user.name=mike&user[age]=3&items[0][sku]=A&other=1")
+	req.Header.Set("Content-Type", MIMEPOSTForm)
+
+	result, err := New().BindWithResult(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Items", "Items[0]", "Items[0].SKU", "User", "User.Name"}, result.Paths())
+	assert.Equal(t, "user.name", result.Fields["User.Name"].Key)
+	assert.Equal(t, "items[0][sku]", result.Fields["Items[0].SKU"].Key)
+	assert.Equal(t, []Input{{"form", "other"}, {"form", "user[age]"}}, result.Ignored)
+}
+
+func TestBindWithResultMultipart(t *testing.T) {
+	var s struct {
+		File *multipart.FileHeader `form:"file"`
+	}
+	req := createRequestMultipartFiles(t, testFile{"file", "file1", []byte("hello")}, testFile{"other", "file2", []byte("world")})
+
+	result, err := BindWithResult(req, &s)
+	assert.NoError(t, err)
+	assert.Equal(t, FieldSource{Source: "form", Key: "file"}, result.Fields["File"])
+	assert.Contains(t, result.Ignored, Input{"form", "other"})
+	assert.NotContains(t, result.Ignored, Input{"form", "file"})
+}
@@ -0,0 +1,115 @@
+package binding
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnknownParams request has parameters which no field binds in strict mode
+var ErrUnknownParams = errors.New("unknown parameters")
+
+// UnknownParamsError lists every request parameter of a strict source which
+// no field bound. It wraps ErrUnknownParams.
+type UnknownParamsError struct {
+	Params []Input // sorted by source and key
+}
+
+func (e *UnknownParamsError) Error() string {
+	params := make([]string, len(e.Params))
+	for i, in := range e.Params {
+		params[i] = fmt.Sprintf("%s %q", in.Source, in.Key)
+	}
+	return ErrUnknownParams.Error() + ": " + strings.Join(params, ", ")
+}
+
+func (e *UnknownParamsError) Unwrap() error {
+	return ErrUnknownParams
+}
+
+// ParamAllowlist is implemented by bind targets which accept parameters no
+// field binds in strict mode, such as tracking parameters. A name ending in
+// "*" allows every parameter with its prefix, header names match
+// case-insensitively, body keys are JSON Pointers such as "/meta/trace".
+//
+//	func (*Search) AllowedParams(source string) []string {
+//		if source == "query" {
+//			return []string{"utm_*", "fbclid"}
+//		}
+//		return nil
+//	}
+type ParamAllowlist interface {
+	AllowedParams(source string) []string
+}
+
+// SetStrict makes the package level Binding reject requests with parameters
+// which no field binds, for each of the given sources.
+//
+//	binding.SetStrict("query", "json")
+func SetStrict(sources ...string) {
+	defaultBinding.SetStrict(sources...)
+}
+
+// SetStrict makes b reject requests with parameters which no field binds, for
+// each of the sources "query", "form", "header", "uri", "json", "xml", "yaml"
+// and "toml". The error is an *UnknownParamsError naming all of them.
+func (b *Binding) SetStrict(sources ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.strict == nil {
+		b.strict = make(map[string]bool)
+	}
+	for _, source := range sources {
+		b.strict[source] = true
+	}
+}
+
+func (b *Binding) isStrict(source string) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.strict[source]
+}
+
+func (b *Binding) hasStrict() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.strict) > 0
+}
+
+// checkStrict returns an UnknownParamsError for the inputs of strict sources
+// which rec saw no field consume.
+func (b *Binding) checkStrict(obj interface{}, rec *recorder) error {
+	if rec == nil || !b.hasStrict() {
+		return nil
+	}
+	allowlist, _ := obj.(ParamAllowlist)
+
+	var unknown []Input
+	for in, used := range rec.inputs {
+		if !used && b.isStrict(in.Source) && !isAllowedParam(allowlist, in) {
+			unknown = append(unknown, in)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sortInputs(unknown)
+	return &UnknownParamsError{Params: unknown}
+}
+
+func isAllowedParam(allowlist ParamAllowlist, in Input) bool {
+	if allowlist == nil {
+		return false
+	}
+	key := in.Key
+	for _, allowed := range allowlist.AllowedParams(in.Source) {
+		if in.Source == "header" {
+			key, allowed = strings.ToLower(key), strings.ToLower(allowed)
+		}
+		if prefix := strings.TrimSuffix(allowed, "*"); prefix != allowed && strings.HasPrefix(key, prefix) || allowed == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package binding
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type strictSearch struct {
+	Page     int    `query:"page" json:"page" xml:"page" yaml:"page" toml:"page"`
+	PageSize int    `query:"page_size" json:"page_size" xml:"page_size,attr" yaml:"page_size" toml:"page_size"`
+	Token    string `header:"X-Token"`
+}
+
+func (*strictSearch) AllowedParams(source string) []string {
+	switch source {
+	case "query":
+		return []string{"utm_*", "fbclid"}
+	case "header":
+		return []string{"user-agent"}
+	}
+	return nil
+}
+
+func TestBindingStrictQuery(t *testing.T) {
+	b := New()
+	b.SetStrict("query", "header")
+
+	var s strictSearch
+	req := requestWithBody("GET", "/?page=2&page_szie=10&utm_source=mail&fbclid=x&sort=asc", "")
+	req.Header.Set("X-Token", "t")
+	req.Header.Set("User-Agent", "test")
+	req.Header.Set("X-Debug", "1")
+	err := b.Bind(req, &s)
+
+	var unknown *UnknownParamsError
+	if assert.ErrorAs(t, err, &unknown) {
+		assert.Equal(t, []Input{
+			{"header", "X-Debug"},
+			{"query", "page_szie"},
+			{"query", "sort"},
+		}, unknown.Params)
+	}
+	assert.ErrorIs(t, err, ErrUnknownParams)
+	assert.EqualError(t, err, `unknown parameters: header "X-Debug", query "page_szie", query "sort"`)
+
+	req = requestWithBody("GET", "/?page=2&page_size=10&utm_campaign=x", "")
+	assert.NoError(t, b.Bind(req, &s))
+	assert.Equal(t, 10, s.PageSize)
+
+	// other Bindings are lenient
+	req = requestWithBody("GET", "/?page_szie=10", "")
+	assert.NoError(t, Bind(req, &s))
+	assert.NoError(t, New().Bind(req, &s))
+
+	// strict sources are checked without fields of their tag
+	var form struct {
+		Name string `form:"name"`
+	}
+	b = New()
+	b.SetStrict("form")
+	req = requestWithBody("POST", "/", "name=a&nmae=b")
+	req.Header.Set("Content-Type", MIMEPOSTForm)
+	err = b.Bind(req, &form)
+	assert.EqualError(t, err, `unknown parameters: form "nmae"`)
+
+	// the form binder reads the query too, query keys are strict by their own source
+	err = b.BindWith(requestWithBody("POST", "/?other=b", "name=a"), &form, Form)
+	assert.NoError(t, err)
+	b.SetStrict("query")
+	err = b.BindWith(requestWithBody("POST", "/?other=b", "name=a"), &form, Form)
+	assert.EqualError(t, err, `unknown parameters: query "other"`)
+}
+
+func TestBindingStrictBody(t *testing.T) {
+	b := New()
+	b.SetStrict("json", "xml", "yaml", "toml")
+
+	for _, tt := range []struct {
+		contentType string
+		body        string
+		err         string
+	}{
+		{MIMEJSON, `{"page":1,"paeg":2,"extra":{"a":1}}`, `unknown parameters: json "/extra", json "/paeg"`},
+		{MIMEXML, `<s page_size="1" sort="x"><page>1</page><paeg>2</paeg></s>`, `unknown parameters: xml "/@sort", xml "/paeg"`},
+		{MIMEYAML, "page: 1\nPage_Size: 2\n", `unknown parameters: yaml "/Page_Size"`},
+		{MIMETOML, "page = 1\nPage_Size = 2\nsize = 3\n", `unknown parameters: toml "/size"`},
+	} {
+		var s strictSearch
+		req := requestWithBody("POST", "/", tt.body)
+		req.Header.Set("Content-Type", tt.contentType)
+		err := b.Bind(req, &s)
+		assert.EqualError(t, err, tt.err, tt.contentType)
+		assert.Equal(t, 1, s.Page, tt.contentType)
+	}
+}
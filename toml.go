@@ -2,13 +2,21 @@ package binding
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
+	"reflect"
 
 	"github.com/pelletier/go-toml/v2"
 )
 
-type tomlBinding struct{}
+type tomlBinding struct {
+	rec *recorder
+}
+
+func (tomlBinding) withBinding(_ *Binding, rec *recorder) Binder {
+	return tomlBinding{rec}
+}
 
 func decodeToml(r io.Reader, obj interface{}) error {
 	decoder := toml.NewDecoder(r)
@@ -18,10 +26,133 @@ func decodeToml(r io.Reader, obj interface{}) error {
 	return decoder.Decode(obj)
 }
 
-func (tomlBinding) Bind(req *http.Request, obj interface{}) error {
-	return decodeToml(req.Body, obj)
+func (tb tomlBinding) Bind(req *http.Request, obj interface{}) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return tb.BindBody(body, obj)
+}
+
+func (tb tomlBinding) BindBody(body []byte, obj interface{}) error {
+	if err := bindTOML(body, obj); err != nil {
+		return err
+	}
+	if tb.rec == nil {
+		return nil
+	}
+	return tb.rec.recordTOML(body, obj)
+}
+
+// bindTOML decodes the TOML document body into obj. Times are parsed by the
+// time tags of their fields.
+func bindTOML(body []byte, obj interface{}) error {
+	if obj == nil || !hasTimeTags(reflect.TypeOf(obj)) {
+		if err := decodeToml(bytes.NewReader(body), obj); err != nil {
+			return tomlError(err, body, obj)
+		}
+		return nil
+	}
+
+	var doc interface{}
+	if err := toml.Unmarshal(body, &doc); err != nil {
+		return tomlError(err, body, obj)
+	}
+	var times []bodyTime
+	doc, err := parseDocTimes(tomlFormat, doc, reflect.TypeOf(obj), nil, nil, "", &times)
+	if err != nil {
+		return err
+	}
+	data, err := toml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := toml.Unmarshal(data, obj); err != nil {
+		return err
+	}
+	setBodyTimes(tomlFormat, obj, times)
+	return nil
+}
+
+func (tb tomlBinding) bindAt(req *http.Request, pointer string, obj interface{}) error {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := toml.Unmarshal(body, &doc); err != nil {
+		return tomlError(err, body, nil)
+	}
+	value, err := getJSONValue(doc, tokens)
+	if err != nil {
+		return nil
+	}
+	var times []bodyTime
+	if hasTimeTags(reflect.TypeOf(obj)) {
+		if value, err = parseDocTimes(tomlFormat, value, reflect.TypeOf(obj), nil, nil, pointer, &times); err != nil {
+			return err
+		}
+	}
+	if err := decodeTOMLValue(value, obj); err != nil {
+		return err
+	}
+	setBodyTimes(tomlFormat, obj, times)
+	if tb.rec != nil {
+		tb.rec.recordDoc(tomlFormat, value, reflect.TypeOf(obj), "", pointer)
+	}
+	return nil
+}
+
+// decodeTOMLValue decodes value, a part of a TOML document decoded into maps
+// and slices, into obj. TOML documents are tables, so value is encoded back
+// as the only key of one.
+func decodeTOMLValue(value interface{}, obj interface{}) error {
+	data, err := toml.Marshal(map[string]interface{}{"value": value})
+	if err != nil {
+		return err
+	}
+	ptr := reflect.ValueOf(obj)
+	table := reflect.New(reflect.StructOf([]reflect.StructField{
+		{Name: "Value", Type: ptr.Type(), Tag: `toml:"value"`},
+	}))
+	table.Elem().Field(0).Set(ptr)
+	return toml.Unmarshal(data, table.Interface())
 }
 
-func (tomlBinding) BindBody(body []byte, obj interface{}) error {
-	return decodeToml(bytes.NewReader(body), obj)
+// tomlError locates err, an error decoding the TOML document data into obj.
+// go-toml reports the position of syntax errors, other errors are returned
+// as is.
+func tomlError(err error, data []byte, obj interface{}) error {
+	var decodeErr *toml.DecodeError
+	if !errors.As(err, &decodeErr) {
+		return err
+	}
+	e := &BodyError{Format: "toml", Err: err}
+	e.Line, e.Column = decodeErr.Position()
+	e.Offset = lineOffset(data, e.Line, e.Column)
+	if key := decodeErr.Key(); len(key) > 0 {
+		path := make([]pathElem, len(key))
+		for i, k := range key {
+			path[i] = pathElem{key: k, index: -1}
+		}
+		e.Path = formatJSONPath(path)
+		if obj != nil {
+			e.Field = tomlFormat.fieldPath(reflect.TypeOf(obj), path)
+		}
+	}
+	return e
+}
+
+// recordTOML records the fields of obj populated by the TOML document data.
+func (rec *recorder) recordTOML(data []byte, obj interface{}) error {
+	var doc interface{}
+	if err := toml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	rec.recordDoc(tomlFormat, doc, reflect.TypeOf(obj), "", "")
+	return nil
 }
@@ -1,7 +1,11 @@
 package binding
 
-type uriBinding struct{}
+type uriBinding struct {
+	binding *Binding
+	rec     *recorder
+}
 
-func (uriBinding) BindURI(params map[string][]string, obj interface{}) error {
-	return mapFormByTag(obj, params, "uri")
+func (ub uriBinding) BindURI(params map[string][]string, obj interface{}) error {
+	ub.rec.input("uri", params)
+	return ub.binding.mapFormByTag(obj, params, "uri", ub.rec)
 }
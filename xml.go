@@ -5,18 +5,159 @@ import (
 	"encoding/xml"
 	"io"
 	"net/http"
+	"reflect"
+	"strings"
 )
 
-type xmlBinding struct{}
+type xmlBinding struct {
+	rec *recorder
+}
+
+func (xmlBinding) withBinding(_ *Binding, rec *recorder) Binder {
+	return xmlBinding{rec}
+}
+
+func (xb xmlBinding) Bind(req *http.Request, obj interface{}) error {
+	if xb.rec == nil {
+		return decodeXML(req.Body, obj)
+	}
 
-func (xmlBinding) Bind(req *http.Request, obj interface{}) error {
-	return decodeXML(req.Body, obj)
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return xb.BindBody(body, obj)
 }
 
-func (xmlBinding) BindBody(body []byte, obj interface{}) error {
-	return decodeXML(bytes.NewReader(body), obj)
+func (xb xmlBinding) BindBody(body []byte, obj interface{}) error {
+	if err := decodeXML(bytes.NewReader(body), obj); err != nil {
+		return err
+	}
+	if xb.rec == nil {
+		return nil
+	}
+	return xb.rec.recordXML(body, obj)
 }
 func decodeXML(r io.Reader, obj interface{}) error {
 	decoder := xml.NewDecoder(r)
 	return decoder.Decode(obj)
 }
+
+var xmlUnmarshalerType = reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem()
+
+// recordXML records the fields of obj populated by the XML document data.
+// Keys are JSON Pointers of element names, "/item/price", attributes are
+// prefixed with "@", "/item/@id".
+func (rec *recorder) recordXML(data []byte, obj interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return rec.recordXMLElement(decoder, start, reflect.TypeOf(obj), "", "")
+		}
+	}
+}
+
+func (rec *recorder) recordXMLElement(d *xml.Decoder, start xml.StartElement, t reflect.Type, path, pointer string) error {
+	for t.Kind() == reflect.Ptr || (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8 {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t == timeType ||
+		reflect.PtrTo(t).Implements(xmlUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return d.Skip()
+	}
+	fields, anyElem, anyAttr := xmlFields(t)
+
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || anyAttr {
+			continue
+		}
+		key := pointer + "/@" + escapeJSONPointer(attr.Name.Local)
+		if sf, ok := fields["@"+attr.Name.Local]; ok {
+			rec.set(joinFieldPath(path, sf.Name), "xml", key, false)
+		} else {
+			rec.input("xml", map[string][]string{key: nil})
+		}
+	}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			key := pointer + "/" + escapeJSONPointer(tok.Name.Local)
+			sf, ok := fields[tok.Name.Local]
+			if !ok || anyElem {
+				if !anyElem {
+					rec.input("xml", map[string][]string{key: nil})
+				}
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			fieldPath := joinFieldPath(path, sf.Name)
+			rec.set(fieldPath, "xml", key, false)
+			if err := rec.recordXMLElement(d, tok, sf.Type, fieldPath, key); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// xmlFields returns the fields of t by element name, attributes by "@" and
+// their name, and whether t takes any element or attribute.
+func xmlFields(t reflect.Type) (fields map[string]reflect.StructField, anyElem, anyAttr bool) {
+	fields = make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("xml")
+		if tag == "-" || sf.Name == "XMLName" {
+			continue
+		}
+		name, opts := head(tag, ",")
+		flags := "," + opts + ","
+		if sf.Anonymous && tag == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				promoted, elem, attr := xmlFields(ft)
+				for k, psf := range promoted {
+					psf.Index = append([]int{i}, psf.Index...)
+					fields[k] = psf
+				}
+				anyElem, anyAttr = anyElem || elem, anyAttr || attr
+				continue
+			}
+		}
+		if sf.PkgPath != "" {
+			continue
+		}
+		name, _ = head(name, ">")
+		if name == "" {
+			name = sf.Name
+		}
+		switch {
+		case strings.Contains(flags, ",attr,"):
+			if strings.Contains(flags, ",any,") {
+				anyAttr = true
+			}
+			fields["@"+name] = sf
+		case strings.Contains(flags, ",any,"), strings.Contains(flags, ",innerxml,"):
+			anyElem = true
+		case strings.Contains(flags, ",chardata,"), strings.Contains(flags, ",cdata,"), strings.Contains(flags, ",comment,"):
+		default:
+			fields[name] = sf
+		}
+	}
+	return fields, anyElem, anyAttr
+}
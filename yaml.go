@@ -2,23 +2,339 @@ package binding
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
-type yamlBinding struct{}
+type yamlBinding struct {
+	rec *recorder
+}
+
+func (yamlBinding) withBinding(_ *Binding, rec *recorder) Binder {
+	return yamlBinding{rec}
+}
+
+func (yb yamlBinding) Bind(req *http.Request, obj interface{}) error {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return yb.BindBody(body, obj)
+}
 
-func (yamlBinding) Bind(req *http.Request, obj interface{}) error {
-	return decodeYAML(req.Body, obj)
+func (yb yamlBinding) BindBody(body []byte, obj interface{}) error {
+	if err := bindYAML(body, obj); err != nil {
+		return err
+	}
+	if yb.rec == nil {
+		return nil
+	}
+	return yb.rec.recordYAML(body, obj)
 }
 
-func (yamlBinding) BindBody(body []byte, obj interface{}) error {
-	return decodeYAML(bytes.NewReader(body), obj)
+// bindYAML decodes the YAML document body into obj.
+func bindYAML(body []byte, obj interface{}) error {
+	if obj == nil || !hasTimeTags(reflect.TypeOf(obj)) {
+		if err := decodeYAML(bytes.NewReader(body), obj); err != nil {
+			return yamlError(err, body, obj)
+		}
+		return nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(body)).Decode(&doc); err != nil {
+		return yamlError(err, body, obj)
+	}
+	return decodeYAMLNode(body, &doc, &doc, nil, obj)
+}
+
+// decodeYAMLNode decodes node, the value at path of the YAML document doc,
+// into obj. Times are parsed by the time tags of their fields.
+func decodeYAMLNode(body []byte, doc, node *yaml.Node, path []pathElem, obj interface{}) error {
+	var tokens []string
+	for _, p := range path {
+		tokens = append(tokens, p.token())
+	}
+
+	w := yamlTimes{body: body, prefix: path, root: reflect.TypeOf(obj)}
+	if obj != nil && hasTimeTags(w.root) {
+		if err := w.parse(node, w.root, nil, nil); err != nil {
+			return err
+		}
+	}
+	if err := node.Decode(obj); err != nil {
+		return yamlErrorAt(err, body, doc, tokens, obj)
+	}
+	setBodyTimes(yamlFormat, obj, w.times)
+	return nil
+}
+
+// yamlTimes parses the times of a YAML document by the time tags of their
+// fields, the nodes of the times are rewritten to RFC 3339 timestamps.
+type yamlTimes struct {
+	body   []byte
+	prefix []pathElem // path of the node decoded into root
+	root   reflect.Type
+	times  []bodyTime
+}
+
+func (w *yamlTimes) parse(node *yaml.Node, t reflect.Type, sf *reflect.StructField, path []pathElem) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil
+		}
+		node = node.Content[0]
+	}
+
+	if t == timeType {
+		if sf == nil || node.Kind != yaml.ScalarNode || node.Tag == "!!null" {
+			return nil
+		}
+		tm, err := parseBodyTime(node.Value, *sf)
+		if err != nil {
+			full := append(w.prefix[:len(w.prefix):len(w.prefix)], path...)
+			return &BodyError{
+				Format: "yaml",
+				Offset: lineOffset(w.body, node.Line, node.Column),
+				Line:   node.Line,
+				Column: node.Column,
+				Path:   formatJSONPath(full),
+				Field:  yamlFormat.fieldPath(w.root, path),
+				Err:    conversionError(jsonPointerOf(full), []string{node.Value}, t, err),
+			}
+		}
+		node.Tag, node.Value, node.Style = "!!timestamp", tm.Format(time.RFC3339Nano), 0
+		w.times = append(w.times, bodyTime{path, tm})
+		return nil
+	}
+
+	switch {
+	case t.Kind() == reflect.Struct && node.Kind == yaml.MappingNode && !yamlFormat.leaf(t):
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+			field, ok := yamlFormat.field(t, key)
+			if !ok {
+				continue
+			}
+			var tagged *reflect.StructField
+			if isTimeTagged(field) {
+				tagged = &field
+			}
+			elem := pathElem{key: key, index: -1}
+			if err := w.parse(node.Content[i+1], field.Type, tagged, append(path[:len(path):len(path)], elem)); err != nil {
+				return err
+			}
+		}
+	case t.Kind() == reflect.Map && node.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			elem := pathElem{key: node.Content[i].Value, index: -1}
+			if err := w.parse(node.Content[i+1], t.Elem(), sf, append(path[:len(path):len(path)], elem)); err != nil {
+				return err
+			}
+		}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && node.Kind == yaml.SequenceNode:
+		for i, child := range node.Content {
+			if err := w.parse(child, t.Elem(), sf, append(path[:len(path):len(path)], pathElem{index: i})); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (yb yamlBinding) bindAt(req *http.Request, pointer string, obj interface{}) error {
+	tokens, err := parseJSONPointer(pointer)
+	if err != nil {
+		return err
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(body)).Decode(&doc); err != nil {
+		return yamlError(err, body, nil)
+	}
+	node, path := yamlSubtree(&doc, tokens)
+	if node == nil {
+		return nil
+	}
+	if err := decodeYAMLNode(body, &doc, node, path, obj); err != nil {
+		return err
+	}
+	if yb.rec == nil {
+		return nil
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return err
+	}
+	yb.rec.recordDoc(yamlFormat, value, reflect.TypeOf(obj), "", pointer)
+	return nil
+}
+
+// yamlSubtree returns the node of the YAML document doc at the reference
+// tokens of a JSON Pointer and its path, nil when there is none.
+func yamlSubtree(doc *yaml.Node, tokens []string) (*yaml.Node, []pathElem) {
+	node := doc
+	var path []pathElem
+	for _, token := range tokens {
+		for node.Kind == yaml.DocumentNode || node.Kind == yaml.AliasNode {
+			if node.Kind == yaml.AliasNode {
+				node = node.Alias
+			} else if len(node.Content) > 0 {
+				node = node.Content[0]
+			} else {
+				return nil, nil
+			}
+		}
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			var value *yaml.Node
+			for i := 0; i+1 < len(node.Content) && value == nil; i += 2 {
+				if node.Content[i].Value == token {
+					value = node.Content[i+1]
+				}
+			}
+			if value == nil {
+				return nil, nil
+			}
+			node = value
+			path = append(path, pathElem{key: token, index: -1})
+		case yaml.SequenceNode:
+			i, err := jsonArrayIndex(token, len(node.Content)-1)
+			if err != nil {
+				return nil, nil
+			}
+			node = node.Content[i]
+			path = append(path, pathElem{index: i})
+		default:
+			return nil, nil
+		}
+	}
+	return node, path
 }
 
 func decodeYAML(r io.Reader, obj interface{}) error {
 	decoder := yaml.NewDecoder(r)
 	return decoder.Decode(obj)
 }
+
+var (
+	yamlLineRegexp  = regexp.MustCompile(`\bline (\d+)`)
+	yamlValueRegexp = regexp.MustCompile("`([^`]*)`")
+)
+
+// yamlError locates err, an error decoding the YAML document data into obj.
+// Errors which can not be located are returned as is.
+func yamlError(err error, data []byte, obj interface{}) error {
+	return yamlErrorAt(err, data, nil, nil, obj)
+}
+
+// yamlErrorAt locates err, an error decoding the value at the reference
+// tokens of a JSON Pointer of the YAML document data into obj, doc is data
+// decoded into nodes when at hand.
+func yamlErrorAt(err error, data []byte, doc *yaml.Node, tokens []string, obj interface{}) error {
+	msg := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		msg = typeErr.Errors[0]
+	}
+	m := yamlLineRegexp.FindStringSubmatch(msg)
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	e := &BodyError{Format: "yaml", Line: line, Err: err}
+
+	if typeErr != nil {
+		if doc == nil {
+			doc = new(yaml.Node)
+			if yaml.Unmarshal(data, doc) != nil {
+				doc = nil
+			}
+		}
+		var value string
+		if m := yamlValueRegexp.FindStringSubmatch(msg); m != nil {
+			value = m[1]
+		}
+		if node, path := yamlNodeAt(doc, line, value); node != nil {
+			e.Column, e.Path = node.Column, formatJSONPath(path)
+			if len(path) >= len(tokens) && obj != nil {
+				e.Field = yamlFormat.fieldPath(reflect.TypeOf(obj), path[len(tokens):])
+			}
+		}
+	}
+	e.Offset = lineOffset(data, e.Line, e.Column)
+	return e
+}
+
+// yamlNodeAt returns the value node of doc on line, the one whose value is
+// value if any, and its path.
+func yamlNodeAt(doc *yaml.Node, line int, value string) (*yaml.Node, []pathElem) {
+	var found *yaml.Node
+	var foundPath []pathElem
+	value = strings.TrimSuffix(value, "...")
+
+	var walk func(node *yaml.Node, path []pathElem) bool
+	walk = func(node *yaml.Node, path []pathElem) bool {
+		if node.Line == line && node.Kind != yaml.DocumentNode {
+			if node.Kind == yaml.ScalarNode && value != "" && strings.HasPrefix(node.Value, value) {
+				found, foundPath = node, path
+				return true
+			}
+			if found == nil {
+				found, foundPath = node, path
+			}
+		}
+		switch node.Kind {
+		case yaml.DocumentNode:
+			for _, child := range node.Content {
+				if walk(child, path) {
+					return true
+				}
+			}
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				elem := pathElem{key: node.Content[i].Value, index: -1}
+				if walk(node.Content[i+1], append(path[:len(path):len(path)], elem)) {
+					return true
+				}
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				if walk(child, append(path[:len(path):len(path)], pathElem{index: i})) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if doc != nil {
+		walk(doc, nil)
+	}
+	return found, foundPath
+}
+
+// recordYAML records the fields of obj populated by the YAML document data.
+func (rec *recorder) recordYAML(data []byte, obj interface{}) error {
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	rec.recordDoc(yamlFormat, doc, reflect.TypeOf(obj), "", "")
+	return nil
+}